@@ -1,43 +1,189 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+	"net/netip"
 	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	scalar "github.com/MarceloPetrucio/go-scalar-api-reference"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql"
 	repo "github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql/sqlc"
+	"github.com/Ajay01103/goTransactonsAPI/internal/admin"
 	"github.com/Ajay01103/goTransactonsAPI/internal/auth"
+	"github.com/Ajay01103/goTransactonsAPI/internal/blobstore"
+	"github.com/Ajay01103/goTransactonsAPI/internal/clientip"
+	"github.com/Ajay01103/goTransactonsAPI/internal/health"
+	jsonutil "github.com/Ajay01103/goTransactonsAPI/internal/json"
+	"github.com/Ajay01103/goTransactonsAPI/internal/ledger"
+	"github.com/Ajay01103/goTransactonsAPI/internal/mailer"
+	"github.com/Ajay01103/goTransactonsAPI/internal/metrics"
+	"github.com/Ajay01103/goTransactonsAPI/internal/privacy"
+	"github.com/Ajay01103/goTransactonsAPI/internal/transactions"
 	"github.com/Ajay01103/goTransactonsAPI/internal/users"
 )
 
 type application struct {
-	config config
-	db     *pgx.Conn
+	config  config
+	db      *pgxpool.Pool
+	keys    *auth.KeySet
+	secrets *auth.SecretBox
+	oidc    *auth.OIDCProviders
+	avatars users.AvatarConfig
+	privacy privacy.Config
+
+	workers *workers
+	ready   atomic.Bool // false until serving and again once shutdown starts
+
+	// checks behind /livez and /readyz
+	liveChecks  *health.Registry
+	readyChecks *health.Registry
+
+	metrics *metrics.Metrics // nil when METRICS_ENABLED=false
 }
 
 type config struct {
-	addr      string
-	db        dbConfig
-	jwtSecret string
+	environment     string // development or production; see validate
+	addr            string
+	trustedProxies  []netip.Prefix // reverse proxies whose X-Forwarded-For is believed
+	shutdown        shutdownConfig
+	health          healthConfig
+	metrics         metricsConfig
+	db              dbConfig
+	jwt             jwtConfig
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+	// how long RequireAuth trusts a cached "not revoked" answer
+	revocationCacheTTL time.Duration
+
+	passwordResetTTL time.Duration
+	passwordResetURL string
+	mail             mailConfig
+
+	magicLinkTTL time.Duration
+	magicLinkURL string
+
+	emailChangeTTL time.Duration
+	emailChangeURL string
+
+	emailVerification emailVerificationConfig
+
+	totpEncryptionKey string // base64 AES key; derived from the JWT secret when empty
+	totpIssuer        string
+	mfaChallengeTTL   time.Duration
+
+	loginThrottle auth.LoginThrottleConfig
+
+	oidcProviders []auth.OIDCProviderConfig
+	oidcStateTTL  time.Duration
+
+	avatar avatarConfig
+
+	privacy privacyConfig
+}
+
+// shutdownConfig controls how the server stops on SIGINT or SIGTERM.
+type shutdownConfig struct {
+	// how long /readyz reports 503 before the listener closes, so load
+	// balancers stop sending new requests first
+	readinessDelay time.Duration
+	// how long in-flight requests, background workers and the database pool
+	// together get to finish
+	timeout time.Duration
+}
+
+// healthConfig bounds the /livez and /readyz checks.
+type healthConfig struct {
+	timeout  time.Duration // per check
+	cacheTTL time.Duration // how long a report is reused
+}
+
+// metricsConfig controls the Prometheus /metrics endpoint.
+type metricsConfig struct {
+	enabled bool
+	// serve /metrics on this separate listener, e.g. ":9090", rather than
+	// next to the API, so it need not be exposed publicly
+	addr string
+}
+
+// avatarConfig selects where uploaded avatars are stored: "s3" uses an
+// S3-compatible bucket, anything else (the default, "local") a directory.
+type avatarConfig struct {
+	store        string
+	localDir     string
+	s3           blobstore.S3Config
+	maxBytes     int64
+	maxDimension int
+	urlSecret    string // signs avatar URLs; derived from the JWT secret when empty
+	baseURL      string
+	urlTTL       time.Duration
+}
+
+// privacyConfig controls data exports and account deletion. Export archives
+// are kept in the avatar store.
+type privacyConfig struct {
+	exportTTL     time.Duration
+	exportURL     string
+	deletionGrace time.Duration
+}
+
+type emailVerificationConfig struct {
+	policy         auth.VerificationPolicy // which requests unverified accounts may not make
+	ttl            time.Duration
+	url            string
+	resendCooldown time.Duration
+}
+
+// mailConfig selects the Mailer: "smtp" delivers through smtp, anything else
+// (the default, "log") only logs messages.
+type mailConfig struct {
+	driver string
+	smtp   mailer.SMTPConfig
+}
+
+// jwtConfig selects the access-token signing keys. When signingKeyFile is
+// empty tokens are signed with the HS256 secret instead (development only).
+type jwtConfig struct {
+	secret               string
+	signingKeyFile       string   // RSA or Ed25519 private key PEM
+	verificationKeyFiles []string // previous keys still accepted during rotation
 }
 
 type dbConfig struct {
-	dsn string
+	pool        postgresql.PoolConfig
+	txIsolation pgx.TxIsoLevel
+
+	// apply pending migrations at startup instead of refusing to serve
+	migrateOnStart bool
+	// where "migrate create" writes new migration files
+	migrationDir string
 }
 
 func (app *application) mount() http.Handler {
 	r := chi.NewRouter()
 
+	// request counts and latencies by route pattern; first, so it times everything
+	if app.metrics != nil {
+		r.Use(app.metrics.Middleware)
+	}
+
 	// A good base middleware stack
 	r.Use(middleware.RequestID) // important for rate limiting
-	r.Use(middleware.RealIP)    // import for rate limiting and analytics and tracing
+	// client address for login throttling and logs; forwarding headers only count from TRUSTED_PROXIES
+	r.Use(clientip.Middleware(app.config.trustedProxies))
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer) // recover from crashes
 
@@ -46,9 +192,20 @@ func (app *application) mount() http.Handler {
 	// processing should be stopped.
 	r.Use(middleware.Timeout(60 * time.Second))
 
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte("all good"))
-	})
+	// public keys for verifying our access tokens
+	r.Get("/.well-known/jwks.json", auth.JWKSHandler(app.keys))
+
+	// probes: /readyz is 503 while a dependency is down and once shutdown
+	// has started, so load balancers stop routing here
+	probes := health.NewHandler(app.liveChecks, app.readyChecks, app.ready.Load)
+	r.Get("/livez", probes.Livez)
+	r.Get("/readyz", probes.Readyz)
+	r.Get("/health", probes.Readyz) // older name for /readyz
+
+	// Prometheus metrics, unless they have a listener of their own
+	if app.metrics != nil && app.config.metrics.addr == "" {
+		r.Handle("/metrics", app.metrics.Handler())
+	}
 
 	// serve the raw OpenAPI spec so Scalar can fetch it
 	r.Get("/docs/swagger.json", func(w http.ResponseWriter, r *http.Request) {
@@ -235,27 +392,193 @@ func (app *application) mount() http.Handler {
 		fmt.Fprintln(w, htmlContent)
 	})
 
+	// one Queries over the pool and one unit-of-work manager, shared by every
+	// repository and service — the pool is safe for concurrent use
+	queries := repo.New(app.db)
+	txManager := postgresql.NewTxManager(app.db, queries, postgresql.WithIsolation(app.config.db.txIsolation))
+
 	// auth routes
-	authRepo := auth.NewPostgresRepository(repo.New(app.db))
-	authService := auth.NewService(authRepo, app.config.jwtSecret)
+	authRepo := auth.NewPostgresRepository(queries)
+	revocations := auth.NewRevocationStore(authRepo, app.config.revocationCacheTTL)
+	app.workers.Go("token revocations", revocations.Run, time.Minute)
+
+	loginThrottle := auth.NewLoginThrottle(authRepo, app.config.loginThrottle)
+	app.workers.Go("login throttle", loginThrottle.Run, time.Hour)
+
+	authService := auth.NewService(authRepo, txManager, revocations, loginThrottle, app.mailer(), auth.Config{
+		Keys:            app.keys,
+		AccessTokenTTL:  app.config.accessTokenTTL,
+		RefreshTokenTTL: app.config.refreshTokenTTL,
+
+		PasswordResetTTL: app.config.passwordResetTTL,
+		PasswordResetURL: app.config.passwordResetURL,
+
+		EmailVerificationTTL:       app.config.emailVerification.ttl,
+		EmailVerificationURL:       app.config.emailVerification.url,
+		VerificationResendCooldown: app.config.emailVerification.resendCooldown,
+
+		MagicLinkTTL: app.config.magicLinkTTL,
+		MagicLinkURL: app.config.magicLinkURL,
+
+		EmailChangeTTL: app.config.emailChangeTTL,
+		EmailChangeURL: app.config.emailChangeURL,
+
+		Secrets:         app.secrets,
+		TOTPIssuer:      app.config.totpIssuer,
+		MFAChallengeTTL: app.config.mfaChallengeTTL,
+
+		OIDC:         app.oidc,
+		OIDCStateTTL: app.config.oidcStateTTL,
+
+		AccountDeletionGrace: app.privacy.DeletionGrace,
+
+		Logins: app.loginRecorder(),
+		Tasks:  app.workers,
+	})
+	// requireSession accepts only user access tokens; requireAuth also takes API keys
+	requireSession := auth.RequireAuth(app.keys, revocations, nil)
+	requireAuth := auth.RequireAuth(app.keys, revocations, authService)
+	requireVerified := auth.RequireVerifiedEmail(app.config.emailVerification.policy, authService)
 	authHandler := auth.NewHandler(authService)
 	r.Route("/auth", func(r chi.Router) {
 		r.Post("/register", authHandler.Register)
 		r.Post("/login", authHandler.Login)
+		r.Post("/refresh", authHandler.Refresh)
+		r.Post("/password/forgot", authHandler.ForgotPassword)
+		r.Post("/password/reset", authHandler.ResetPassword)
+		r.Post("/magic-link", authHandler.RequestMagicLink)
+		r.Get("/magic-link/consume", authHandler.ConsumeMagicLink)
+		r.Get("/verify-email", authHandler.VerifyEmail)
+		r.Get("/email-change/confirm", authHandler.ConfirmEmailChange)
+		r.Post("/2fa/verify", authHandler.VerifyMFA)
+		r.Get("/oidc/{provider}/start", authHandler.OIDCStart)
+		r.Get("/oidc/{provider}/callback", authHandler.OIDCCallback)
+
+		r.Group(func(r chi.Router) {
+			r.Use(requireSession)
+			r.Post("/logout", authHandler.Logout)
+			r.Post("/logout-all", authHandler.LogoutAll)
+			r.Post("/verify-email/resend", authHandler.ResendVerification)
+			r.Post("/2fa/totp/setup", authHandler.SetupTOTP)
+			r.Post("/2fa/totp/confirm", authHandler.ConfirmTOTP)
+		})
 	})
 
 	// users routes (protected)
-	usersRepo := users.NewPostgresRepository(repo.New(app.db))
-	usersService := users.NewService(usersRepo)
-	usersHandler := users.NewHandler(usersService)
+	usersRepo := users.NewPostgresRepository(queries)
+	usersService := users.NewService(usersRepo, app.avatars)
+	usersHandler := users.NewHandler(usersService, app.avatars.MaxBytes)
+
+	// data exports and the purge of deleted accounts
+	privacyRepo := privacy.NewPostgresRepository(queries)
+	privacyService := privacy.NewService(privacyRepo, txManager, app.mailer(), app.privacy)
+	app.workers.Go("privacy", privacyService.Run, time.Minute)
+	privacyHandler := privacy.NewHandler(privacyService)
+
 	r.Route("/users", func(r chi.Router) {
-		r.Use(auth.RequireAuth(app.config.jwtSecret))
-		r.Get("/current-user", usersHandler.GetCurrentUser)
+		r.With(requireAuth, auth.RequireScope("users")).Get("/current-user", usersHandler.GetCurrentUser)
+
+		// the account and its API keys can be managed only from a user session, never with a key
+		r.Group(func(r chi.Router) {
+			r.Use(requireSession)
+			r.Patch("/me", usersHandler.UpdateProfile)
+			r.Delete("/me", authHandler.DeleteAccount)
+			r.Put("/me/avatar", usersHandler.UploadAvatar)
+			r.Post("/me/password", authHandler.ChangePassword)
+			r.Post("/me/email", authHandler.RequestEmailChange)
+			r.Post("/me/api-keys", authHandler.CreateAPIKey)
+			r.Get("/me/api-keys", authHandler.ListAPIKeys)
+			r.Delete("/me/api-keys/{id}", authHandler.DeleteAPIKey)
+			r.Get("/me/sessions", authHandler.ListSessions)
+			r.Delete("/me/sessions/{id}", authHandler.DeleteSession)
+			r.Post("/me/export", privacyHandler.RequestExport)
+			r.Get("/me/exports", privacyHandler.ListExports)
+		})
+	})
+
+	// avatar thumbnails — public, but only through the signed URLs in user responses
+	r.Get("/avatars/*", usersHandler.ServeAvatar)
+
+	// export archives — public, but only through the link emailed to the owner
+	r.Get("/exports/download", privacyHandler.DownloadExport)
+
+	// admin routes — user sessions holding the admin role only; every action is audited
+	adminRepo := admin.NewPostgresRepository(queries)
+	adminService := admin.NewService(adminRepo, authService)
+	adminHandler := admin.NewHandler(adminService)
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(requireSession)
+		r.Use(auth.RequireRole(auth.RoleAdmin))
+		r.Get("/users", adminHandler.ListUsers)
+		r.Post("/users/{id}/lock", adminHandler.LockUser)
+		r.Post("/users/{id}/unlock", adminHandler.UnlockUser)
+		r.Post("/users/{id}/password-reset", adminHandler.ForcePasswordReset)
+		r.Post("/users/{id}/restore", adminHandler.RestoreUser)
+		r.Get("/audit-log", adminHandler.ListAuditLog)
+	})
+
+	// connection pool counters (acquired, idle, waits); internals, so admins only
+	r.Group(func(r chi.Router) {
+		r.Use(requireSession)
+		r.Use(auth.RequireRole(auth.RoleAdmin))
+		r.Get("/debug/db/stats", func(w http.ResponseWriter, r *http.Request) {
+			jsonutil.Write(w, http.StatusOK, postgresql.Stats(app.db))
+		})
+	})
+
+	// transactions routes (protected, every query scoped to the caller's userID)
+	transactionsRepo := transactions.NewPostgresRepository(queries)
+	transactionsService := transactions.NewService(transactionsRepo)
+	transactionsHandler := transactions.NewHandler(transactionsService)
+	r.Route("/transactions", func(r chi.Router) {
+		r.Use(requireAuth)
+		r.Use(auth.RequireScope("transactions"))
+		r.Use(requireVerified)
+		r.Get("/", transactionsHandler.List)
+		r.Post("/", transactionsHandler.Create)
+		r.Get("/{id}", transactionsHandler.Get)
+		r.Patch("/{id}", transactionsHandler.Update)
+		r.Delete("/{id}", transactionsHandler.Delete)
+	})
+
+	// ledger routes (protected) — double-entry accounts and journal entries
+	ledgerRepo := ledger.NewPostgresRepository(txManager, queries)
+	ledgerService := ledger.NewService(ledgerRepo)
+	ledgerHandler := ledger.NewHandler(ledgerService)
+	r.Route("/ledger", func(r chi.Router) {
+		r.Use(requireAuth)
+		r.Use(auth.RequireScope("ledger"))
+		r.Use(requireVerified)
+		r.Get("/accounts", ledgerHandler.ListAccounts)
+		r.Post("/accounts", ledgerHandler.CreateAccount)
+		r.Get("/accounts/{id}", ledgerHandler.GetAccount)
+		r.Get("/entries", ledgerHandler.ListEntries)
+		r.Post("/entries", ledgerHandler.CreateEntry)
+		r.Get("/entries/{id}", ledgerHandler.GetEntry)
 	})
 
 	return r
 }
 
+// loginRecorder returns the metrics as an auth.LoginRecorder, or nil when
+// they are disabled.
+func (app *application) loginRecorder() auth.LoginRecorder {
+	if app.metrics == nil {
+		return nil
+	}
+	return app.metrics
+}
+
+func (app *application) mailer() mailer.Mailer {
+	if app.config.mail.driver == "smtp" {
+		return mailer.NewSMTPMailer(app.config.mail.smtp)
+	}
+	return mailer.NewLogMailer(slog.Default())
+}
+
+// run serves h, and the metrics listener if one is configured, until SIGINT
+// or SIGTERM, then shuts down. It returns an error if a server failed or the
+// shutdown was not clean.
 func (app *application) run(h http.Handler) error {
 	srv := &http.Server{
 		Addr: app.config.addr,
@@ -264,8 +587,89 @@ func (app *application) run(h http.Handler) error {
 		ReadTimeout:  time.Second * 10,
 		IdleTimeout:  time.Minute,
 	}
+	servers := []*http.Server{srv}
+	if app.metrics != nil && app.config.metrics.addr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", app.metrics.Handler())
+		servers = append(servers, &http.Server{
+			Addr:         app.config.metrics.addr,
+			Handler:      mux,
+			WriteTimeout: time.Second * 30,
+			ReadTimeout:  time.Second * 10,
+		})
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, len(servers))
+	for _, s := range servers {
+		go func() {
+			log.Printf("server has started at addr %s", s.Addr)
+			serveErr <- s.ListenAndServe()
+		}()
+	}
+	app.ready.Store(true)
+
+	select {
+	case err := <-serveErr:
+		// a listener failed; close the others, then stop workers and the pool
+		for _, s := range servers {
+			s.Close()
+		}
+		return errors.Join(err, app.shutdown())
+	case <-ctx.Done():
+	}
+
+	// a second signal kills the process right away
+	stop()
+	slog.Info("shutting down", "readiness_delay", app.config.shutdown.readinessDelay, "timeout", app.config.shutdown.timeout)
+	return app.shutdown(servers...)
+}
+
+// shutdown stops the API in order: readiness fails, then in-flight requests
+// drain, background workers stop and finally the database pool closes. The
+// steps after the readiness delay share one timeout; once it has passed the
+// rest still run, without waiting, and the result reports it. servers is
+// empty when nothing is being served.
+func (app *application) shutdown(servers ...*http.Server) error {
+	app.ready.Store(false)
+	if len(servers) > 0 {
+		time.Sleep(app.config.shutdown.readinessDelay)
+	}
 
-	log.Printf("server has started at addr %s", app.config.addr)
+	ctx, cancel := context.WithTimeout(context.Background(), app.config.shutdown.timeout)
+	defer cancel()
+
+	var errs []error
+	for _, srv := range servers {
+		if err := srv.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("draining requests on %s: %w", srv.Addr, err))
+			srv.Close()
+		}
+	}
+	if err := app.workers.Stop(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := closePool(ctx, app.db); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
 
-	return srv.ListenAndServe()
+// closePool closes the pool, which waits for every acquired connection to be
+// released, or gives up waiting once ctx is done.
+func closePool(ctx context.Context, pool *pgxpool.Pool) error {
+	done := make(chan struct{})
+	go func() {
+		pool.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("closing database pool: %w", ctx.Err())
+	}
 }
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql"
+	"github.com/Ajay01103/goTransactonsAPI/internal/auth"
+	"github.com/Ajay01103/goTransactonsAPI/internal/blobstore"
+	"github.com/Ajay01103/goTransactonsAPI/internal/clientip"
+	"github.com/Ajay01103/goTransactonsAPI/internal/env"
+	"github.com/Ajay01103/goTransactonsAPI/internal/mailer"
+)
+
+// APP_ENV values. Production refuses to start with the development defaults
+// for secrets and the database.
+const (
+	envDevelopment = "development"
+	envProduction  = "production"
+)
+
+const (
+	defaultJWTSecret   = "change-me-in-production"
+	defaultDatabaseURL = "host=localhost user=postgres password=123 dbname=godb sslmode=disable"
+
+	// minSecretLength is the shortest JWT_SECRET accepted in production.
+	minSecretLength = 32
+)
+
+// loadConfig reads every setting through l and validates the result. It
+// returns the config even when it is invalid, so it can still be printed.
+func loadConfig(l *env.Loader) (config, error) {
+	// the goose CLI reads the same DSN from GOOSE_DBSTRING
+	l.Alias("DATABASE_URL", "GOOSE_DBSTRING")
+	l.Alias("MIGRATION_DIR", "GOOSE_MIGRATION_DIR")
+
+	var errs []error
+	txIsolation, err := postgresql.ParseIsolation(l.String("DB_TX_ISOLATION", "read committed"))
+	if err != nil {
+		errs = append(errs, fmt.Errorf("DB_TX_ISOLATION: %w", err))
+	}
+	verificationPolicy, err := auth.ParseVerificationPolicy(l.String("EMAIL_VERIFICATION_POLICY", "off"))
+	if err != nil {
+		errs = append(errs, fmt.Errorf("EMAIL_VERIFICATION_POLICY: %w", err))
+	}
+	trustedProxies, err := clientip.ParseTrusted(l.List("TRUSTED_PROXIES", nil))
+	if err != nil {
+		errs = append(errs, fmt.Errorf("TRUSTED_PROXIES: %w", err))
+	}
+
+	cfg := config{
+		environment:    l.String("APP_ENV", envDevelopment),
+		addr:           l.String("ADDR", ":8000"),
+		trustedProxies: trustedProxies,
+		shutdown: shutdownConfig{
+			readinessDelay: l.Duration("SHUTDOWN_READINESS_DELAY", 0),
+			timeout:        l.Duration("SHUTDOWN_TIMEOUT", 30*time.Second),
+		},
+		metrics: metricsConfig{
+			enabled: l.Bool("METRICS_ENABLED", true),
+			addr:    l.String("METRICS_ADDR", ""),
+		},
+		health: healthConfig{
+			timeout:  l.Duration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+			cacheTTL: l.Duration("HEALTH_CACHE_TTL", 5*time.Second),
+		},
+		db: dbConfig{
+			pool: postgresql.PoolConfig{
+				DSN:               l.Secret("DATABASE_URL", defaultDatabaseURL),
+				MaxConns:          int32(l.Int("DB_MAX_CONNS", 10)),
+				MinConns:          int32(l.Int("DB_MIN_CONNS", 2)),
+				MaxConnLifetime:   l.Duration("DB_MAX_CONN_LIFETIME", time.Hour),
+				MaxConnIdleTime:   l.Duration("DB_MAX_CONN_IDLE_TIME", 30*time.Minute),
+				HealthCheckPeriod: l.Duration("DB_HEALTH_CHECK_PERIOD", time.Minute),
+			},
+			txIsolation:    txIsolation,
+			migrateOnStart: l.Bool("MIGRATE_ON_START", false),
+			migrationDir:   l.String("MIGRATION_DIR", "./internal/adapters/postgresql/migrations"),
+		},
+		jwt: jwtConfig{
+			secret:               l.Secret("JWT_SECRET", defaultJWTSecret),
+			signingKeyFile:       l.String("JWT_SIGNING_KEY_FILE", ""),
+			verificationKeyFiles: l.List("JWT_VERIFICATION_KEY_FILES", nil),
+		},
+		accessTokenTTL:  l.Duration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		refreshTokenTTL: l.Duration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+
+		revocationCacheTTL: l.Duration("TOKEN_REVOCATION_CACHE_TTL", 30*time.Second),
+
+		passwordResetTTL: l.Duration("PASSWORD_RESET_TTL", time.Hour),
+		passwordResetURL: l.String("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+		magicLinkTTL:     l.Duration("MAGIC_LINK_TTL", 15*time.Minute),
+		magicLinkURL:     l.String("MAGIC_LINK_URL", "http://localhost:8000/auth/magic-link/consume"),
+		emailChangeTTL:   l.Duration("EMAIL_CHANGE_TTL", 24*time.Hour),
+		emailChangeURL:   l.String("EMAIL_CHANGE_URL", "http://localhost:8000/auth/email-change/confirm"),
+		mail: mailConfig{
+			driver: l.String("MAIL_DRIVER", "log"),
+			smtp: mailer.SMTPConfig{
+				Host:     l.String("SMTP_HOST", "localhost"),
+				Port:     l.Int("SMTP_PORT", 1025),
+				Username: l.String("SMTP_USERNAME", ""),
+				Password: l.Secret("SMTP_PASSWORD", ""),
+				From:     l.String("MAIL_FROM", "no-reply@localhost"),
+			},
+		},
+		emailVerification: emailVerificationConfig{
+			policy:         verificationPolicy,
+			ttl:            l.Duration("EMAIL_VERIFICATION_TTL", 24*time.Hour),
+			url:            l.String("EMAIL_VERIFICATION_URL", "http://localhost:8000/auth/verify-email"),
+			resendCooldown: l.Duration("EMAIL_VERIFICATION_RESEND_COOLDOWN", time.Minute),
+		},
+		totpEncryptionKey: l.Secret("TOTP_ENCRYPTION_KEY", ""),
+		totpIssuer:        l.String("TOTP_ISSUER", "Go Transactions API"),
+		mfaChallengeTTL:   l.Duration("MFA_CHALLENGE_TTL", 5*time.Minute),
+
+		loginThrottle: auth.LoginThrottleConfig{
+			MaxFailures:   l.Int("LOGIN_MAX_FAILURES", 5),
+			MaxIPFailures: l.Int("LOGIN_MAX_IP_FAILURES", 50),
+			BaseLockout:   l.Duration("LOGIN_LOCKOUT_BASE", time.Minute),
+			MaxLockout:    l.Duration("LOGIN_LOCKOUT_MAX", time.Hour),
+		},
+
+		oidcProviders: oidcProviderConfigs(l, l.List("OIDC_PROVIDERS", nil)),
+		oidcStateTTL:  l.Duration("OIDC_STATE_TTL", 10*time.Minute),
+
+		avatar: avatarConfig{
+			store:    l.String("AVATAR_STORE", "local"),
+			localDir: l.String("AVATAR_LOCAL_DIR", "./data/avatars"),
+			s3: blobstore.S3Config{
+				Endpoint:  l.String("S3_ENDPOINT", "localhost:9000"),
+				Region:    l.String("S3_REGION", ""),
+				Bucket:    l.String("S3_BUCKET", "avatars"),
+				AccessKey: l.String("S3_ACCESS_KEY", ""),
+				SecretKey: l.Secret("S3_SECRET_KEY", ""),
+				UseSSL:    l.Bool("S3_USE_SSL", false),
+			},
+			maxBytes:     int64(l.Int("AVATAR_MAX_BYTES", 5<<20)),
+			maxDimension: l.Int("AVATAR_MAX_DIMENSION", 4096),
+			urlSecret:    l.Secret("AVATAR_URL_SECRET", ""),
+			baseURL:      l.String("AVATAR_BASE_URL", "http://localhost:8000"),
+			urlTTL:       l.Duration("AVATAR_URL_TTL", 24*time.Hour),
+		},
+
+		privacy: privacyConfig{
+			exportTTL:     l.Duration("DATA_EXPORT_TTL", 72*time.Hour),
+			exportURL:     l.String("DATA_EXPORT_URL", "http://localhost:8000/exports/download"),
+			deletionGrace: l.Duration("ACCOUNT_DELETION_GRACE", 30*24*time.Hour),
+		},
+	}
+
+	errs = append(errs, l.Err(), cfg.validate())
+	return cfg, errors.Join(errs...)
+}
+
+// validate checks settings that parse but make no sense, and in production
+// refuses the development defaults for secrets.
+func (c config) validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.environment == envDevelopment || c.environment == envProduction,
+		"APP_ENV: must be %s or %s, not %q", envDevelopment, envProduction, c.environment)
+	check(c.addr != "", "ADDR: must not be empty")
+	check(c.db.pool.MaxConns > 0, "DB_MAX_CONNS: must be positive")
+	check(c.db.pool.MinConns >= 0 && c.db.pool.MinConns <= c.db.pool.MaxConns,
+		"DB_MIN_CONNS: must be between 0 and DB_MAX_CONNS")
+	check(c.accessTokenTTL > 0, "ACCESS_TOKEN_TTL: must be positive")
+	check(c.refreshTokenTTL > c.accessTokenTTL, "REFRESH_TOKEN_TTL: must be longer than ACCESS_TOKEN_TTL")
+	check(c.shutdown.timeout > 0, "SHUTDOWN_TIMEOUT: must be positive")
+	check(c.shutdown.readinessDelay >= 0, "SHUTDOWN_READINESS_DELAY: must not be negative")
+	check(c.metrics.addr == "" || c.metrics.addr != c.addr, "METRICS_ADDR: must differ from ADDR; leave it empty to serve /metrics on ADDR")
+	check(c.health.timeout > 0, "HEALTH_CHECK_TIMEOUT: must be positive")
+	check(c.health.cacheTTL >= 0, "HEALTH_CACHE_TTL: must not be negative")
+	check(c.avatar.maxBytes > 0, "AVATAR_MAX_BYTES: must be positive")
+	check(c.avatar.urlTTL > 0, "AVATAR_URL_TTL: must be positive")
+	check(c.privacy.exportTTL > 0, "DATA_EXPORT_TTL: must be positive")
+	check(c.privacy.deletionGrace >= 0, "ACCOUNT_DELETION_GRACE: must not be negative")
+
+	if c.environment == envProduction {
+		check(c.jwt.secret != defaultJWTSecret && len(c.jwt.secret) >= minSecretLength,
+			"JWT_SECRET: must be a random value of at least %d characters in production; other secrets are derived from it", minSecretLength)
+		check(c.totpEncryptionKey != "", "TOTP_ENCRYPTION_KEY: must be set in production")
+		check(c.db.pool.DSN != defaultDatabaseURL, "DATABASE_URL: must be set in production")
+		check(c.mail.driver == "smtp", "MAIL_DRIVER: must be smtp in production, or account emails are only logged")
+	}
+	return errors.Join(errs...)
+}
+
+// oidcProviderConfigs reads OIDC_<NAME>_* settings for every provider name.
+// The name is upper-cased and '-' becomes '_' to form the variable prefix.
+func oidcProviderConfigs(l *env.Loader, names []string) []auth.OIDCProviderConfig {
+	configs := make([]auth.OIDCProviderConfig, 0, len(names))
+	for _, name := range names {
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		configs = append(configs, auth.OIDCProviderConfig{
+			Name:         name,
+			Issuer:       l.String(prefix+"ISSUER", ""),
+			ClientID:     l.String(prefix+"CLIENT_ID", ""),
+			ClientSecret: l.Secret(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  l.String(prefix+"REDIRECT_URL", "http://localhost:8000/auth/oidc/"+name+"/callback"),
+			Scopes:       l.List(prefix+"SCOPES", nil),
+		})
+	}
+	return configs
+}
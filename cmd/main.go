@@ -2,44 +2,222 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 
+	"github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql"
+	"github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql/migrations"
+	"github.com/Ajay01103/goTransactonsAPI/internal/auth"
+	"github.com/Ajay01103/goTransactonsAPI/internal/blobstore"
 	"github.com/Ajay01103/goTransactonsAPI/internal/env"
-	"github.com/jackc/pgx/v5"
+	"github.com/Ajay01103/goTransactonsAPI/internal/health"
+	"github.com/Ajay01103/goTransactonsAPI/internal/metrics"
+	"github.com/Ajay01103/goTransactonsAPI/internal/privacy"
+	"github.com/Ajay01103/goTransactonsAPI/internal/users"
 )
 
 func main() {
+	args := os.Args[1:]
+	if len(args) >= 2 && args[0] == "config" && args[1] == "print" {
+		os.Exit(printConfig(args[2:]))
+	}
+	if len(args) >= 1 && args[0] == "migrate" {
+		os.Exit(runMigrate(args[1:]))
+	}
+
 	ctx := context.Background()
 
-	cfg := config{
-		addr: ":8000",
-		db: dbConfig{
-			dsn: env.GetString("GOOSE_DBSTRING", "host=localhost user=postgres password=123 dbname=godb sslmode=disable"),
-		},
-		jwtSecret: env.GetString("JWT_SECRET", "change-me-in-production"),
+	l, err := env.Load(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	cfg, err := loadConfig(l)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+		os.Exit(2)
 	}
 
 	// Logger
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	slog.SetDefault(logger)
 
+	// Access-token signing keys
+	var keys *auth.KeySet
+	if cfg.jwt.signingKeyFile != "" {
+		keys, err = auth.LoadKeySet(cfg.jwt.signingKeyFile, cfg.jwt.verificationKeyFiles)
+		if err != nil {
+			panic(err)
+		}
+		logger.Info("loaded jwt signing key", "kid", keys.SigningKeyID(), "verification_keys", len(cfg.jwt.verificationKeyFiles))
+	} else {
+		keys = auth.NewHMACKeySet(cfg.jwt.secret)
+		logger.Warn("JWT_SIGNING_KEY_FILE not set, signing tokens with the HS256 JWT_SECRET")
+	}
+
+	// Encryption key for secrets stored at rest (TOTP seeds)
+	var secretKey []byte
+	if encoded := cfg.totpEncryptionKey; encoded != "" {
+		secretKey, err = base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			panic(fmt.Errorf("decoding TOTP_ENCRYPTION_KEY: %w", err))
+		}
+	} else {
+		sum := sha256.Sum256([]byte("totp-encryption:" + cfg.jwt.secret))
+		secretKey = sum[:]
+		logger.Warn("TOTP_ENCRYPTION_KEY not set, deriving it from JWT_SECRET")
+	}
+	secrets, err := auth.NewSecretBox(secretKey)
+	if err != nil {
+		panic(err)
+	}
+
+	// External identity providers; discovery happens on first use
+	oidcProviders, err := auth.NewOIDCProviders(cfg.oidcProviders)
+	if err != nil {
+		panic(err)
+	}
+	if names := oidcProviders.Names(); len(names) > 0 {
+		logger.Info("oidc providers configured", "providers", names)
+	}
+
+	// File storage for avatars and data exports, and avatar URL signing
+	var blobs blobstore.BlobStore
+	if cfg.avatar.store == "s3" {
+		blobs, err = blobstore.NewS3Store(ctx, cfg.avatar.s3)
+	} else {
+		blobs, err = blobstore.NewLocalStore(cfg.avatar.localDir)
+	}
+	if err != nil {
+		panic(err)
+	}
+	avatarURLSecret := []byte(cfg.avatar.urlSecret)
+	if len(avatarURLSecret) == 0 {
+		sum := sha256.Sum256([]byte("avatar-urls:" + cfg.jwt.secret))
+		avatarURLSecret = sum[:]
+		logger.Warn("AVATAR_URL_SECRET not set, deriving it from JWT_SECRET")
+	}
+
+	// Metrics; the pool's tracer times every query
+	var appMetrics *metrics.Metrics
+	if cfg.metrics.enabled {
+		appMetrics = metrics.New()
+		cfg.db.pool.Tracer = appMetrics
+	}
+
 	// Database
-	conn, err := pgx.Connect(ctx, cfg.db.dsn)
+	// closed by api.run once the server and background workers have stopped
+	pool, err := postgresql.NewPool(ctx, cfg.db.pool)
+	if err != nil {
+		panic(err)
+	}
+	if appMetrics != nil {
+		appMetrics.RegisterPool(pool)
+	}
+
+	logger.Info("connected to database", "max_conns", cfg.db.pool.MaxConns)
+
+	// Schema: apply pending migrations, or refuse to serve an outdated schema
+	migrator, err := postgresql.NewMigrator(pool, migrations.FS)
 	if err != nil {
 		panic(err)
 	}
-	defer conn.Close(ctx)
+	if cfg.db.migrateOnStart {
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			logger.Info("applied migration", "migration", m.Name)
+		}
+		if err != nil {
+			logger.Error("migrating database", "error", err)
+			pool.Close()
+			os.Exit(1)
+		}
+	} else if err := migrator.Check(ctx); err != nil {
+		logger.Error("refusing to start: run \"migrate up\" or start with --migrate-on-start", "error", err)
+		pool.Close()
+		os.Exit(1)
+	}
 
-	logger.Info("connected to database", "dsn", cfg.db.dsn)
+	// Health checks, cached so probes cannot overload the database
+	liveChecks := health.NewRegistry(cfg.health.timeout, cfg.health.cacheTTL)
+	readyChecks := health.NewRegistry(cfg.health.timeout, cfg.health.cacheTTL)
+	readyChecks.Register("database", health.CheckerFunc(pool.Ping))
+	readyChecks.Register("migrations", migrator)
+	readyChecks.RegisterOptional("database_pool", health.CheckerFunc(func(context.Context) error {
+		return postgresql.CheckSaturation(pool)
+	}))
+	if c, ok := blobs.(health.HealthChecker); ok {
+		readyChecks.RegisterOptional("blob_store", c)
+	}
 
 	api := application{
-		config: cfg,
-		db:     conn,
+		config:  cfg,
+		db:      pool,
+		keys:    keys,
+		secrets: secrets,
+		oidc:    oidcProviders,
+		avatars: users.AvatarConfig{
+			Store:        blobs,
+			MaxBytes:     cfg.avatar.maxBytes,
+			MaxDimension: cfg.avatar.maxDimension,
+			URLSecret:    avatarURLSecret,
+			BaseURL:      strings.TrimSuffix(cfg.avatar.baseURL, "/"),
+			URLTTL:       cfg.avatar.urlTTL,
+		},
+		privacy: privacy.Config{
+			Store:             blobs,
+			ExportTTL:         cfg.privacy.exportTTL,
+			ExportDownloadURL: cfg.privacy.exportURL,
+			DeletionGrace:     cfg.privacy.deletionGrace,
+		},
+		workers: newWorkers(),
+
+		liveChecks:  liveChecks,
+		readyChecks: readyChecks,
+
+		metrics: appMetrics,
+	}
+	if c, ok := api.mailer().(health.HealthChecker); ok {
+		readyChecks.RegisterOptional("mailer", c)
 	}
 
 	if err := api.run(api.mount()); err != nil {
-		slog.Error("server failed to start", "error", err)
+		slog.Error("server stopped uncleanly", "error", err)
 		os.Exit(1)
 	}
-}
\ No newline at end of file
+	slog.Info("server stopped")
+}
+
+// printConfig implements "config print [--redacted] [settings flags]": it
+// prints every setting with where its value came from, then any problems.
+func printConfig(args []string) int {
+	redacted := false
+	rest := args[:0:0]
+	for _, arg := range args {
+		if arg == "--redacted" || arg == "-redacted" {
+			redacted = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+
+	l, err := env.Load(rest)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	_, cfgErr := loadConfig(l)
+	if err := l.Print(os.Stdout, redacted); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if cfgErr != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", cfgErr)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql"
+	"github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql/migrations"
+	"github.com/Ajay01103/goTransactonsAPI/internal/env"
+)
+
+const migrateUsage = "usage: migrate up|down|redo|status|create NAME [settings flags]"
+
+// migrationName is what "migrate create" accepts as a migration name.
+var migrationName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// runMigrate implements "migrate COMMAND [settings flags]" against the
+// migrations embedded in the binary. It returns the process exit code.
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	command, args := args[0], args[1:]
+
+	var name string
+	if command == "create" {
+		if len(args) == 0 || strings.HasPrefix(args[0], "-") || !migrationName.MatchString(args[0]) {
+			fmt.Fprintln(os.Stderr, "usage: migrate create NAME, where NAME is lower_snake_case")
+			return 2
+		}
+		name, args = args[0], args[1:]
+	}
+
+	l, err := env.Load(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	cfg, err := loadConfig(l)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+		return 2
+	}
+
+	if command == "create" {
+		path, err := createMigration(cfg.db.migrationDir, name, time.Now())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("created %s\nrebuild the binary to embed it\n", path)
+		return 0
+	}
+
+	ctx := context.Background()
+	pool, err := postgresql.NewPool(ctx, cfg.db.pool)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer pool.Close()
+
+	migrator, err := postgresql.NewMigrator(pool, migrations.FS)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied %s\n", m.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+	case "down", "redo":
+		migrate, verb := migrator.Down, "rolled back"
+		if command == "redo" {
+			migrate, verb = migrator.Redo, "redid"
+		}
+		m, err := migrate(ctx)
+		if errors.Is(err, postgresql.ErrNoMigrations) {
+			fmt.Println(err)
+			return 0
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("%s %s\n", verb, m.Name)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		printMigrationStatus(statuses)
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	return 0
+}
+
+// printMigrationStatus writes one line per migration, like goose status.
+func printMigrationStatus(statuses []postgresql.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "APPLIED AT\tMIGRATION")
+	for _, s := range statuses {
+		appliedAt := "pending"
+		if !s.AppliedAt.IsZero() {
+			appliedAt = s.AppliedAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "%s\t%s\n", appliedAt, s.Name)
+	}
+	w.Flush()
+}
+
+// createMigration writes an empty <timestamp>_<name>.sql migration to dir,
+// versioned by the UTC time as goose does.
+func createMigration(dir, name string, now time.Time) (string, error) {
+	path := filepath.Join(dir, now.UTC().Format("20060102150405")+"_"+name+".sql")
+	const template = `-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+`
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("creating migration: %w", err)
+	}
+	if _, err := f.WriteString(template); err != nil {
+		f.Close()
+		return "", fmt.Errorf("creating migration: %w", err)
+	}
+	return path, f.Close()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// workers runs the API's background loops under one context, and one-off
+// tasks such as email deliveries, so shutdown can stop them together and wait
+// until they have returned.
+type workers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newWorkers() *workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &workers{ctx: ctx, cancel: cancel}
+}
+
+// Go starts a Run(ctx, interval) loop in the background until Stop is called.
+func (w *workers) Go(name string, run func(ctx context.Context, interval time.Duration), interval time.Duration) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		run(w.ctx, interval)
+		slog.Info("background worker stopped", "worker", name)
+	}()
+}
+
+// Do runs task in the background. Stop does not cancel tasks; it waits for
+// them to finish.
+func (w *workers) Do(task func()) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		task()
+	}()
+}
+
+// Stop cancels every worker and waits for all of them, and every task, to
+// return, or until ctx is done.
+func (w *workers) Stop(ctx context.Context) error {
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for background workers and tasks: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,143 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+var (
+	// ErrNoMigrations is returned by Down and Redo when nothing is applied.
+	ErrNoMigrations = errors.New("no migrations applied")
+
+	// ErrSchemaBehind is returned by Check when migrations are pending.
+	ErrSchemaBehind = errors.New("database schema is behind")
+)
+
+// Migration is one goose-format SQL migration.
+type Migration struct {
+	Version int64
+	Name    string // file name
+}
+
+// MigrationStatus is a migration and when it was applied.
+type MigrationStatus struct {
+	Migration
+	AppliedAt time.Time // zero while pending
+}
+
+// Migrator applies the migrations of a directory to the database with goose.
+// Changes hold goose's Postgres advisory lock, so replicas starting at once
+// apply each migration exactly once.
+type Migrator struct {
+	provider *goose.Provider
+}
+
+// NewMigrator reads every <version>_<name>.sql file in fsys.
+func NewMigrator(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("creating migration lock: %w", err)
+	}
+	// the *sql.DB keeps no idle connections, so it borrows from pool only while migrating
+	provider, err := goose.NewProvider(goose.DialectPostgres, stdlib.OpenDBFromPool(pool), fsys,
+		goose.WithSessionLocker(locker))
+	if err != nil {
+		return nil, fmt.Errorf("loading migrations: %w", err)
+	}
+	return &Migrator{provider: provider}, nil
+}
+
+// Up applies every pending migration in version order and returns them. If
+// one fails, the migrations applied before it are still returned.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	results, err := m.provider.Up(ctx)
+	if partial := (*goose.PartialError)(nil); errors.As(err, &partial) {
+		results = partial.Applied
+	}
+
+	applied := make([]Migration, 0, len(results))
+	for _, r := range results {
+		applied = append(applied, toMigration(r.Source))
+	}
+	return applied, err
+}
+
+// Down rolls back the most recently applied migration and returns it.
+func (m *Migrator) Down(ctx context.Context) (Migration, error) {
+	result, err := m.provider.Down(ctx)
+	if errors.Is(err, goose.ErrNoNextVersion) {
+		return Migration{}, ErrNoMigrations
+	}
+	if err != nil {
+		return Migration{}, err
+	}
+	return toMigration(result.Source), nil
+}
+
+// Redo rolls back the most recently applied migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) (Migration, error) {
+	migration, err := m.Down(ctx)
+	if err != nil {
+		return Migration{}, err
+	}
+	if _, err := m.provider.ApplyVersion(ctx, migration.Version, true); err != nil {
+		return migration, err
+	}
+	return migration, nil
+}
+
+// Status lists every known migration with when it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	results, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(results))
+	for i, r := range results {
+		statuses[i] = MigrationStatus{Migration: toMigration(r.Source)}
+		if r.State == goose.StateApplied {
+			statuses[i].AppliedAt = r.AppliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// Check returns an error wrapping ErrSchemaBehind unless every migration is
+// applied. Versions applied but unknown here, from a newer build, are fine.
+func (m *Migrator) Check(ctx context.Context) error {
+	// HasPending takes no lock, so a probe is not held up by a running migration
+	pending, err := m.provider.HasPending(ctx)
+	if err != nil {
+		return fmt.Errorf("reading schema version: %w", err)
+	}
+	if !pending {
+		return nil
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: reading pending migrations: %w", ErrSchemaBehind, err)
+	}
+	var names []string
+	for _, s := range statuses {
+		if s.AppliedAt.IsZero() {
+			names = append(names, s.Name)
+		}
+	}
+	return fmt.Errorf("%w: %d pending migration(s): %s", ErrSchemaBehind, len(names), strings.Join(names, ", "))
+}
+
+func toMigration(src *goose.Source) Migration {
+	return Migration{Version: src.Version, Name: path.Base(src.Path)}
+}
@@ -0,0 +1,24 @@
+-- +goose Up
+
+-- +goose StatementBegin
+CREATE TABLE transactions (
+	id          text          PRIMARY KEY,
+	user_id     text          NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	amount      numeric(19,4) NOT NULL CHECK (amount > 0),
+	type        text          NOT NULL CHECK (type IN ('income', 'expense')),
+	category    text          NOT NULL,
+	description text,
+	occurred_at timestamptz   NOT NULL,
+	created_at  timestamptz   NOT NULL DEFAULT now(),
+	updated_at  timestamptz   NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX transactions_user_id_occurred_at_idx ON transactions (user_id, occurred_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS transactions;
+-- +goose StatementEnd
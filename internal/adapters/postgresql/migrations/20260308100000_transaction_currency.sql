@@ -0,0 +1,16 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- Existing rows predate multi-currency support and were all recorded in USD.
+ALTER TABLE transactions
+	ADD COLUMN currency text NOT NULL DEFAULT 'USD' CHECK (currency ~ '^[A-Z]{3}$');
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE transactions ALTER COLUMN currency DROP DEFAULT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE transactions DROP COLUMN IF EXISTS currency;
+-- +goose StatementEnd
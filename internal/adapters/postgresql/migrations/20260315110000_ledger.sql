@@ -0,0 +1,105 @@
+-- +goose Up
+
+-- +goose StatementBegin
+CREATE TABLE accounts (
+	id         text        PRIMARY KEY,
+	user_id    text        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	name       text        NOT NULL,
+	type       text        NOT NULL CHECK (type IN ('asset', 'liability', 'income', 'expense', 'equity')),
+	currency   text        NOT NULL CHECK (currency ~ '^[A-Z]{3}$'),
+	created_at timestamptz NOT NULL DEFAULT now(),
+	updated_at timestamptz NOT NULL DEFAULT now(),
+	UNIQUE (user_id, name),
+	-- lets postings reference (account_id, currency) so a posting can never
+	-- be recorded in a currency other than its account's
+	UNIQUE (id, currency)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE journal_entries (
+	id          text        PRIMARY KEY,
+	user_id     text        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	description text        NOT NULL,
+	occurred_at timestamptz NOT NULL,
+	created_at  timestamptz NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX journal_entries_user_id_occurred_at_idx ON journal_entries (user_id, occurred_at DESC);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- A positive amount debits the account, a negative amount credits it.
+CREATE TABLE postings (
+	id               text          PRIMARY KEY,
+	journal_entry_id text          NOT NULL REFERENCES journal_entries (id) ON DELETE CASCADE,
+	line             integer       NOT NULL,
+	account_id       text          NOT NULL,
+	amount           numeric(19,4) NOT NULL CHECK (amount <> 0),
+	currency         text          NOT NULL,
+	created_at       timestamptz   NOT NULL DEFAULT now(),
+	UNIQUE (journal_entry_id, line),
+	FOREIGN KEY (account_id, currency) REFERENCES accounts (id, currency)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX postings_account_id_idx ON postings (account_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION check_journal_entry_balanced() RETURNS trigger AS $$
+DECLARE
+	entry_id   text;
+	unbalanced text;
+BEGIN
+	IF TG_OP = 'DELETE' THEN
+		entry_id := OLD.journal_entry_id;
+	ELSE
+		entry_id := NEW.journal_entry_id;
+	END IF;
+
+	SELECT currency INTO unbalanced
+	FROM postings
+	WHERE journal_entry_id = entry_id
+	GROUP BY currency
+	HAVING sum(amount) <> 0
+	LIMIT 1;
+
+	IF unbalanced IS NOT NULL THEN
+		RAISE EXCEPTION 'journal entry % does not balance in %', entry_id, unbalanced
+			USING ERRCODE = 'check_violation';
+	END IF;
+
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Deferred to COMMIT so all postings of an entry can be inserted one by one
+-- inside a single transaction before the balance is checked.
+CREATE CONSTRAINT TRIGGER postings_balanced
+	AFTER INSERT OR UPDATE OR DELETE ON postings
+	DEFERRABLE INITIALLY DEFERRED
+	FOR EACH ROW EXECUTE FUNCTION check_journal_entry_balanced();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS postings;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS check_journal_entry_balanced();
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS journal_entries;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS accounts;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- Opaque refresh tokens, stored only as SHA-256 hashes. Every token issued by
+-- rotating another shares its family_id, so replaying a used token can revoke
+-- the whole chain.
+CREATE TABLE refresh_tokens (
+	id         text        PRIMARY KEY,
+	user_id    text        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	family_id  text        NOT NULL,
+	token_hash text        NOT NULL UNIQUE,
+	expires_at timestamptz NOT NULL,
+	used_at    timestamptz,
+	revoked_at timestamptz,
+	created_at timestamptz NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX refresh_tokens_family_id_idx ON refresh_tokens (family_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX refresh_tokens_user_id_idx ON refresh_tokens (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS refresh_tokens;
+-- +goose StatementEnd
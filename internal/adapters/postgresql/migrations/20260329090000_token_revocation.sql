@@ -0,0 +1,30 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- Bumped by "log out everywhere"; access tokens carrying an older version are rejected.
+ALTER TABLE users ADD COLUMN token_version integer NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Access tokens revoked before their natural expiry, keyed by the JWT "jti".
+-- Rows are pruned once expires_at has passed, since the token is dead anyway.
+CREATE TABLE revoked_tokens (
+	jti        text        PRIMARY KEY,
+	user_id    text        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	expires_at timestamptz NOT NULL,
+	revoked_at timestamptz NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX revoked_tokens_expires_at_idx ON revoked_tokens (expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS revoked_tokens;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS token_version;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- Single-use password reset tokens, stored only as SHA-256 hashes.
+CREATE TABLE password_reset_tokens (
+	id         text        PRIMARY KEY,
+	user_id    text        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	token_hash text        NOT NULL UNIQUE,
+	expires_at timestamptz NOT NULL,
+	used_at    timestamptz,
+	created_at timestamptz NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX password_reset_tokens_user_id_idx ON password_reset_tokens (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS password_reset_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN email_verified_at timestamptz;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Single-use email verification tokens, stored only as SHA-256 hashes.
+-- created_at doubles as the send log used to throttle resends.
+CREATE TABLE email_verification_tokens (
+	id         text        PRIMARY KEY,
+	user_id    text        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	token_hash text        NOT NULL UNIQUE,
+	expires_at timestamptz NOT NULL,
+	used_at    timestamptz,
+	created_at timestamptz NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX email_verification_tokens_user_id_created_at_idx ON email_verification_tokens (user_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS email_verification_tokens;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
+-- +goose StatementEnd
@@ -0,0 +1,53 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- One TOTP enrollment per user. The secret is AES-GCM encrypted by the
+-- application; confirmed_at stays NULL until the first code is verified.
+-- last_used_step is the last accepted 30-second step, so a code cannot be replayed.
+CREATE TABLE user_totp (
+	user_id          text        PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+	secret_encrypted bytea       NOT NULL,
+	confirmed_at     timestamptz,
+	last_used_step   bigint      NOT NULL DEFAULT 0,
+	created_at       timestamptz NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Single-use recovery codes, stored only as SHA-256 hashes.
+CREATE TABLE recovery_codes (
+	id         text        PRIMARY KEY,
+	user_id    text        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	code_hash  text        NOT NULL,
+	used_at    timestamptz,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	UNIQUE (user_id, code_hash)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Pending second factor after a correct password. The opaque challenge token
+-- is exchanged at /auth/2fa/verify; attempts caps guessing.
+CREATE TABLE mfa_challenges (
+	id         text        PRIMARY KEY,
+	user_id    text        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	token_hash text        NOT NULL UNIQUE,
+	attempts   integer     NOT NULL DEFAULT 0,
+	expires_at timestamptz NOT NULL,
+	used_at    timestamptz,
+	created_at timestamptz NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS mfa_challenges;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS recovery_codes;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_totp;
+-- +goose StatementEnd
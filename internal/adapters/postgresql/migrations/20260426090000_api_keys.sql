@@ -0,0 +1,26 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- Long-lived personal access tokens for scripts and integrations. Only the
+-- SHA-256 of the key is stored; prefix keeps enough of it to tell keys apart.
+CREATE TABLE api_keys (
+	id           text        PRIMARY KEY,
+	user_id      text        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	name         text        NOT NULL,
+	prefix       text        NOT NULL,
+	key_hash     text        NOT NULL UNIQUE,
+	scopes       text[]      NOT NULL DEFAULT '{}',
+	expires_at   timestamptz,
+	last_used_at timestamptz,
+	created_at   timestamptz NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX api_keys_user_id_idx ON api_keys (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_keys;
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- "user" or "admin"; carried in access tokens and checked by RequireRole.
+ALTER TABLE users ADD COLUMN role text NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin'));
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Set by an admin; locked accounts cannot log in or use API keys.
+ALTER TABLE users ADD COLUMN locked_at timestamptz;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Set by an admin; login is refused until the password is reset by email.
+ALTER TABLE users ADD COLUMN password_reset_required boolean NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Append-only record of admin actions. actor_id is kept even if the admin's
+-- account is later deleted, so it is not a foreign key.
+CREATE TABLE admin_audit_log (
+	id             text        PRIMARY KEY,
+	actor_id       text        NOT NULL,
+	action         text        NOT NULL,
+	target_user_id text        NOT NULL,
+	created_at     timestamptz NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX admin_audit_log_created_at_idx ON admin_audit_log (created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS admin_audit_log;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS password_reset_required;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS locked_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+-- +goose StatementEnd
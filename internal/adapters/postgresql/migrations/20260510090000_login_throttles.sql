@@ -0,0 +1,18 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- Failed login counters, shared by every API replica. key is "email:<address>"
+-- or "ip:<address>"; emails are tracked whether or not an account exists so a
+-- lockout does not reveal which addresses are registered.
+CREATE TABLE login_throttles (
+	key            text        PRIMARY KEY,
+	failures       integer     NOT NULL DEFAULT 0,
+	last_failed_at timestamptz NOT NULL DEFAULT now(),
+	locked_until   timestamptz
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS login_throttles;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- Links an account at an external OpenID Connect provider to a local user.
+-- subject is the provider's stable "sub" claim; email is what the provider
+-- reported at link time and is informational only.
+CREATE TABLE user_identities (
+	provider      text        NOT NULL,
+	subject       text        NOT NULL,
+	user_id       text        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	email         text        NOT NULL,
+	last_login_at timestamptz NOT NULL DEFAULT now(),
+	created_at    timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (provider, subject)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX user_identities_user_id_idx ON user_identities (user_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- In-flight authorization code flows. The state parameter is stored hashed
+-- and consumed by the callback; nonce and code_verifier never leave the server.
+CREATE TABLE oidc_login_states (
+	state_hash    text        PRIMARY KEY,
+	provider      text        NOT NULL,
+	nonce         text        NOT NULL,
+	code_verifier text        NOT NULL,
+	expires_at    timestamptz NOT NULL,
+	created_at    timestamptz NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS oidc_login_states;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_identities;
+-- +goose StatementEnd
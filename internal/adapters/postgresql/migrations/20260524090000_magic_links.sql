@@ -0,0 +1,25 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- Single-use passwordless login links, stored only as SHA-256 hashes.
+-- browser_hash is the hash of a nonce kept in a cookie of the browser that
+-- asked for the link; the link only works in that browser.
+CREATE TABLE magic_link_tokens (
+	id           text        PRIMARY KEY,
+	user_id      text        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	token_hash   text        NOT NULL UNIQUE,
+	browser_hash text        NOT NULL,
+	expires_at   timestamptz NOT NULL,
+	used_at      timestamptz,
+	created_at   timestamptz NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX magic_link_tokens_user_id_idx ON magic_link_tokens (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS magic_link_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- One row per login. id is the refresh-token family_id and the access
+-- token's sid claim, so revoking a row ends the session on every token.
+CREATE TABLE sessions (
+	id           text        PRIMARY KEY,
+	user_id      text        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	user_agent   text        NOT NULL DEFAULT '',
+	ip           text        NOT NULL DEFAULT '',
+	device_name  text        NOT NULL DEFAULT 'Unknown device',
+	created_at   timestamptz NOT NULL DEFAULT now(),
+	last_seen_at timestamptz NOT NULL DEFAULT now(),
+	revoked_at   timestamptz
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX sessions_user_id_idx ON sessions (user_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Tokens without a session row are rejected, so adopt the live logins.
+INSERT INTO sessions (id, user_id, created_at, last_seen_at)
+SELECT family_id, user_id, min(created_at), max(created_at)
+FROM refresh_tokens
+WHERE revoked_at IS NULL
+GROUP BY family_id, user_id
+HAVING max(expires_at) > now();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS sessions;
+-- +goose StatementEnd
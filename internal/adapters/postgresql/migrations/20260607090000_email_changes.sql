@@ -0,0 +1,25 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- Pending email address changes. The address is switched only when the link
+-- sent to new_email is followed; tokens are stored only as SHA-256 hashes.
+-- created_at doubles as the send log used to throttle requests.
+CREATE TABLE email_change_tokens (
+	id         text        PRIMARY KEY,
+	user_id    text        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	new_email  text        NOT NULL,
+	token_hash text        NOT NULL UNIQUE,
+	expires_at timestamptz NOT NULL,
+	used_at    timestamptz,
+	created_at timestamptz NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX email_change_tokens_user_id_created_at_idx ON email_change_tokens (user_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS email_change_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- Blob store prefix of the uploaded avatar's thumbnails; NULL when none was
+-- uploaded. profile_picture remains the free-text URL given at registration.
+ALTER TABLE users ADD COLUMN avatar_key text;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_key;
+-- +goose StatementEnd
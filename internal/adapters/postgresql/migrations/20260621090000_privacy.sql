@@ -0,0 +1,46 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- Set when the user deletes their account. It cannot sign in from then on and
+-- is purged, with everything it owns, once the grace period has passed.
+ALTER TABLE users ADD COLUMN deleted_at timestamptz;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX users_deleted_at_idx ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Data exports requested by users, built in the background. Once ready the ZIP
+-- is in the blob store under blob_key and token_hash unlocks the emailed
+-- download link until expires_at.
+CREATE TABLE data_exports (
+	id           text        PRIMARY KEY,
+	user_id      text        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	status       text        NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'ready', 'failed', 'expired')),
+	blob_key     text,
+	token_hash   text        UNIQUE,
+	size_bytes   bigint,
+	started_at   timestamptz,
+	completed_at timestamptz,
+	expires_at   timestamptz,
+	created_at   timestamptz NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX data_exports_user_id_created_at_idx ON data_exports (user_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX data_exports_open_idx ON data_exports (status, created_at) WHERE status IN ('pending', 'processing', 'ready');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS data_exports;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
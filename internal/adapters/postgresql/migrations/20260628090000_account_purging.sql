@@ -0,0 +1,12 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- Set when the privacy worker starts purging a deleted account. From then on
+-- its files may already be gone, so the account can no longer be restored.
+ALTER TABLE users ADD COLUMN purging_at timestamptz;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS purging_at;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+// Package migrations embeds the goose-format SQL migrations, so the binary
+// can apply them itself (see postgresql.Migrator).
+package migrations
+
+import "embed"
+
+// FS holds every migration file, named <version>_<name>.sql.
+//
+//go:embed *.sql
+var FS embed.FS
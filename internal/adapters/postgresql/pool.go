@@ -0,0 +1,109 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PoolConfig tunes the connection pool. Zero values keep pgxpool's defaults.
+type PoolConfig struct {
+	DSN               string
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	Tracer            pgx.QueryTracer // observes every query, e.g. for metrics; may be nil
+}
+
+// NewPool opens a pgxpool.Pool and verifies connectivity with a ping. Unlike
+// a single *pgx.Conn, the pool is safe to share between concurrent requests.
+func NewPool(ctx context.Context, cfg PoolConfig) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("parsing database config: %w", err)
+	}
+
+	if cfg.MaxConns > 0 {
+		poolCfg.MaxConns = cfg.MaxConns
+	}
+	if cfg.MinConns > 0 {
+		poolCfg.MinConns = cfg.MinConns
+	}
+	if cfg.MaxConnLifetime > 0 {
+		poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.HealthCheckPeriod > 0 {
+		poolCfg.HealthCheckPeriod = cfg.HealthCheckPeriod
+	}
+	if cfg.Tracer != nil {
+		poolCfg.ConnConfig.Tracer = cfg.Tracer
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating pool: %w", err)
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("pinging database: %w", err)
+	}
+
+	return pool, nil
+}
+
+// PoolStats is a JSON-friendly snapshot of pgxpool.Stat.
+type PoolStats struct {
+	MaxConns          int32  `json:"max_conns"`
+	TotalConns        int32  `json:"total_conns"`
+	AcquiredConns     int32  `json:"acquired_conns"`
+	IdleConns         int32  `json:"idle_conns"`
+	ConstructingConns int32  `json:"constructing_conns"`
+	AcquireCount      int64  `json:"acquire_count"`
+	AcquireDuration   string `json:"acquire_duration"`
+	WaitCount         int64  `json:"wait_count"`
+	WaitDuration      string `json:"wait_duration"`
+	CanceledAcquires  int64  `json:"canceled_acquire_count"`
+	NewConnsCount     int64  `json:"new_conns_count"`
+	LifetimeDestroys  int64  `json:"max_lifetime_destroy_count"`
+	IdleDestroys      int64  `json:"max_idle_destroy_count"`
+}
+
+// Stats snapshots the pool's counters. WaitCount/WaitDuration cover acquires
+// that had to wait because every connection was busy — the saturation signal.
+func Stats(pool *pgxpool.Pool) PoolStats {
+	s := pool.Stat()
+	return PoolStats{
+		MaxConns:          s.MaxConns(),
+		TotalConns:        s.TotalConns(),
+		AcquiredConns:     s.AcquiredConns(),
+		IdleConns:         s.IdleConns(),
+		ConstructingConns: s.ConstructingConns(),
+		AcquireCount:      s.AcquireCount(),
+		AcquireDuration:   s.AcquireDuration().String(),
+		WaitCount:         s.EmptyAcquireCount(),
+		WaitDuration:      s.EmptyAcquireWaitTime().String(),
+		CanceledAcquires:  s.CanceledAcquireCount(),
+		NewConnsCount:     s.NewConnsCount(),
+		LifetimeDestroys:  s.MaxLifetimeDestroyCount(),
+		IdleDestroys:      s.MaxIdleDestroyCount(),
+	}
+}
+
+// CheckSaturation fails when every connection allowed is in use, so further
+// queries queue for one.
+func CheckSaturation(pool *pgxpool.Pool) error {
+	s := pool.Stat()
+	if s.AcquiredConns() >= s.MaxConns() {
+		return fmt.Errorf("all %d connections in use", s.MaxConns())
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+-- name: ListUsers :many
+-- An empty search matches every user; otherwise name or email must contain it.
+-- login_locked_until is the brute-force lockout, if one is in force.
+SELECT u.id, u.name, u.email, u.role, u.email_verified_at, u.locked_at, u.password_reset_required, u.deleted_at, u.created_at,
+       t.locked_until AS login_locked_until
+FROM users u
+LEFT JOIN login_throttles t ON t.key = 'email:' || lower(u.email) AND t.locked_until > now()
+WHERE @search::text = ''
+   OR u.name ILIKE '%' || @search || '%'
+   OR u.email ILIKE '%' || @search || '%'
+ORDER BY u.created_at DESC, u.id DESC
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: CountUsers :one
+SELECT count(*)
+FROM users
+WHERE @search::text = ''
+   OR name ILIKE '%' || @search || '%'
+   OR email ILIKE '%' || @search || '%';
+
+-- name: CreateAdminAuditEntry :exec
+INSERT INTO admin_audit_log (
+    id,
+    actor_id,
+    action,
+    target_user_id
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: ListAdminAuditEntries :many
+SELECT *
+FROM admin_audit_log
+ORDER BY created_at DESC, id DESC
+LIMIT $1 OFFSET $2;
@@ -0,0 +1,38 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (
+    id,
+    user_id,
+    name,
+    prefix,
+    key_hash,
+    scopes,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: ListUserAPIKeys :many
+SELECT *
+FROM api_keys
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: GetAPIKeyByHash :one
+-- Keys of locked or deleted accounts are treated as unknown.
+SELECT k.id, k.user_id, k.name, k.prefix, k.key_hash, k.scopes, k.expires_at, k.last_used_at, k.created_at
+FROM api_keys k
+JOIN users u ON u.id = k.user_id
+WHERE k.key_hash = $1 AND u.locked_at IS NULL AND u.deleted_at IS NULL
+LIMIT 1;
+
+-- name: TouchAPIKey :exec
+-- Records use at most once a minute so busy scripts do not write on every request.
+UPDATE api_keys
+SET last_used_at = now()
+WHERE id = $1
+  AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute');
+
+-- name: DeleteUserAPIKey :execrows
+DELETE FROM api_keys
+WHERE id = $1 AND user_id = $2;
@@ -0,0 +1,28 @@
+-- name: CreateEmailChangeToken :exec
+INSERT INTO email_change_tokens (
+    id,
+    user_id,
+    new_email,
+    token_hash,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: GetEmailChangeTokenByHashForUpdate :one
+SELECT *
+FROM email_change_tokens
+WHERE token_hash = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: ConsumeUserEmailChangeTokens :exec
+-- Marks every outstanding email change token of the user as used.
+UPDATE email_change_tokens
+SET used_at = now()
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: CountEmailChangeTokensSince :one
+SELECT count(*)::int AS sent
+FROM email_change_tokens
+WHERE user_id = $1 AND created_at > $2;
@@ -0,0 +1,28 @@
+-- name: CreateEmailVerificationToken :exec
+INSERT INTO email_verification_tokens (
+    id,
+    user_id,
+    token_hash,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: GetEmailVerificationTokenByHashForUpdate :one
+SELECT *
+FROM email_verification_tokens
+WHERE token_hash = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: ConsumeUserEmailVerificationTokens :exec
+-- Marks every outstanding verification token of the user as used.
+UPDATE email_verification_tokens
+SET used_at = now()
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: GetEmailVerificationSendStats :one
+-- How many verification emails the user was sent since $2, and when the last one went out.
+SELECT count(*)::int AS sent, max(created_at)::timestamptz AS last_sent_at
+FROM email_verification_tokens
+WHERE user_id = $1 AND created_at > $2;
@@ -0,0 +1,77 @@
+-- name: CreateAccount :one
+INSERT INTO accounts (
+    id,
+    user_id,
+    name,
+    type,
+    currency
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetAccount :one
+SELECT *
+FROM accounts
+WHERE id = $1 AND user_id = $2
+LIMIT 1;
+
+-- name: ListAccounts :many
+SELECT *
+FROM accounts
+WHERE user_id = $1
+ORDER BY name;
+
+-- name: GetAccountBalance :one
+SELECT COALESCE(SUM(amount), 0)::numeric AS balance
+FROM postings
+WHERE account_id = $1;
+
+-- name: CreateJournalEntry :one
+INSERT INTO journal_entries (
+    id,
+    user_id,
+    description,
+    occurred_at
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetJournalEntry :one
+SELECT *
+FROM journal_entries
+WHERE id = $1 AND user_id = $2
+LIMIT 1;
+
+-- name: ListJournalEntries :many
+SELECT *
+FROM journal_entries
+WHERE user_id = $1
+ORDER BY occurred_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CreatePosting :one
+INSERT INTO postings (
+    id,
+    journal_entry_id,
+    line,
+    account_id,
+    amount,
+    currency
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: ListPostingsByJournalEntry :many
+SELECT *
+FROM postings
+WHERE journal_entry_id = $1
+ORDER BY line;
+
+-- name: ListPostingsByJournalEntries :many
+SELECT *
+FROM postings
+WHERE journal_entry_id = ANY(sqlc.arg('journal_entry_ids')::text[])
+ORDER BY journal_entry_id, line;
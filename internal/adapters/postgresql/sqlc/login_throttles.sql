@@ -0,0 +1,36 @@
+-- name: GetLoginLockedUntil :one
+-- Latest lockout among the given keys; NULL when none is locked.
+SELECT max(locked_until)::timestamptz AS locked_until
+FROM login_throttles
+WHERE key = ANY(@keys::text[]) AND locked_until > now();
+
+-- name: RecordLoginFailure :one
+-- Counts a failure, starting over when the previous one is older than reset_before.
+INSERT INTO login_throttles (
+    key,
+    failures,
+    last_failed_at
+) VALUES (
+    @key, 1, now()
+)
+ON CONFLICT (key) DO UPDATE
+SET failures = CASE
+        WHEN login_throttles.last_failed_at < @reset_before THEN 1
+        ELSE login_throttles.failures + 1
+    END,
+    last_failed_at = now()
+RETURNING failures;
+
+-- name: LockLoginKey :exec
+UPDATE login_throttles
+SET locked_until = $2
+WHERE key = $1;
+
+-- name: ClearLoginFailures :exec
+DELETE FROM login_throttles
+WHERE key = $1;
+
+-- name: DeleteStaleLoginThrottles :execrows
+DELETE FROM login_throttles
+WHERE last_failed_at < $1
+  AND (locked_until IS NULL OR locked_until < now());
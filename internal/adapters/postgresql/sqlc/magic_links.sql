@@ -0,0 +1,29 @@
+-- name: CreateMagicLinkToken :exec
+INSERT INTO magic_link_tokens (
+    id,
+    user_id,
+    token_hash,
+    browser_hash,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: GetMagicLinkTokenByHashForUpdate :one
+-- Locks the row so the same link cannot log in twice concurrently.
+SELECT *
+FROM magic_link_tokens
+WHERE token_hash = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: ConsumeUserMagicLinkTokens :exec
+-- Marks every outstanding magic link of the user as used.
+UPDATE magic_link_tokens
+SET used_at = now()
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: CountMagicLinkTokensSince :one
+SELECT count(*)::int AS sent
+FROM magic_link_tokens
+WHERE user_id = $1 AND created_at > $2;
@@ -5,15 +5,216 @@
 package repo
 
 import (
+	"github.com/Ajay01103/goTransactonsAPI/internal/money"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type User struct {
+type Account struct {
+	ID        string             `json:"id"`
+	UserID    string             `json:"user_id"`
+	Name      string             `json:"name"`
+	Type      string             `json:"type"`
+	Currency  string             `json:"currency"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type AdminAuditLog struct {
+	ID           string             `json:"id"`
+	ActorID      string             `json:"actor_id"`
+	Action       string             `json:"action"`
+	TargetUserID string             `json:"target_user_id"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type ApiKey struct {
+	ID         string             `json:"id"`
+	UserID     string             `json:"user_id"`
+	Name       string             `json:"name"`
+	Prefix     string             `json:"prefix"`
+	KeyHash    string             `json:"key_hash"`
+	Scopes     []string           `json:"scopes"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type DataExport struct {
+	ID          string             `json:"id"`
+	UserID      string             `json:"user_id"`
+	Status      string             `json:"status"`
+	BlobKey     pgtype.Text        `json:"blob_key"`
+	TokenHash   pgtype.Text        `json:"token_hash"`
+	SizeBytes   pgtype.Int8        `json:"size_bytes"`
+	StartedAt   pgtype.Timestamptz `json:"started_at"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type EmailChangeToken struct {
+	ID        string             `json:"id"`
+	UserID    string             `json:"user_id"`
+	NewEmail  string             `json:"new_email"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type EmailVerificationToken struct {
+	ID        string             `json:"id"`
+	UserID    string             `json:"user_id"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type JournalEntry struct {
+	ID          string             `json:"id"`
+	UserID      string             `json:"user_id"`
+	Description string             `json:"description"`
+	OccurredAt  pgtype.Timestamptz `json:"occurred_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type LoginThrottle struct {
+	Key          string             `json:"key"`
+	Failures     int32              `json:"failures"`
+	LastFailedAt pgtype.Timestamptz `json:"last_failed_at"`
+	LockedUntil  pgtype.Timestamptz `json:"locked_until"`
+}
+
+type MagicLinkToken struct {
+	ID          string             `json:"id"`
+	UserID      string             `json:"user_id"`
+	TokenHash   string             `json:"token_hash"`
+	BrowserHash string             `json:"browser_hash"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	UsedAt      pgtype.Timestamptz `json:"used_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type MfaChallenge struct {
+	ID        string             `json:"id"`
+	UserID    string             `json:"user_id"`
+	TokenHash string             `json:"token_hash"`
+	Attempts  int32              `json:"attempts"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type OidcLoginState struct {
+	StateHash    string             `json:"state_hash"`
+	Provider     string             `json:"provider"`
+	Nonce        string             `json:"nonce"`
+	CodeVerifier string             `json:"code_verifier"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type PasswordResetToken struct {
+	ID        string             `json:"id"`
+	UserID    string             `json:"user_id"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type Posting struct {
 	ID             string             `json:"id"`
-	Name           string             `json:"name"`
-	Email          string             `json:"email"`
-	Password       string             `json:"password"`
-	ProfilePicture pgtype.Text        `json:"profile_picture"`
+	JournalEntryID string             `json:"journal_entry_id"`
+	Line           int32              `json:"line"`
+	AccountID      string             `json:"account_id"`
+	Amount         money.Decimal      `json:"amount"`
+	Currency       string             `json:"currency"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+type RecoveryCode struct {
+	ID        string             `json:"id"`
+	UserID    string             `json:"user_id"`
+	CodeHash  string             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type RefreshToken struct {
+	ID        string             `json:"id"`
+	UserID    string             `json:"user_id"`
+	FamilyID  string             `json:"family_id"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type RevokedToken struct {
+	Jti       string             `json:"jti"`
+	UserID    string             `json:"user_id"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+}
+
+type Session struct {
+	ID         string             `json:"id"`
+	UserID     string             `json:"user_id"`
+	UserAgent  string             `json:"user_agent"`
+	Ip         string             `json:"ip"`
+	DeviceName string             `json:"device_name"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	LastSeenAt pgtype.Timestamptz `json:"last_seen_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+}
+
+type Transaction struct {
+	ID          string             `json:"id"`
+	UserID      string             `json:"user_id"`
+	Amount      money.Decimal      `json:"amount"`
+	Type        string             `json:"type"`
+	Category    string             `json:"category"`
+	Description pgtype.Text        `json:"description"`
+	OccurredAt  pgtype.Timestamptz `json:"occurred_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	Currency    string             `json:"currency"`
+}
+
+type User struct {
+	ID                    string             `json:"id"`
+	Name                  string             `json:"name"`
+	Email                 string             `json:"email"`
+	Password              string             `json:"password"`
+	ProfilePicture        pgtype.Text        `json:"profile_picture"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt             pgtype.Timestamptz `json:"updated_at"`
+	TokenVersion          int32              `json:"token_version"`
+	EmailVerifiedAt       pgtype.Timestamptz `json:"email_verified_at"`
+	Role                  string             `json:"role"`
+	LockedAt              pgtype.Timestamptz `json:"locked_at"`
+	PasswordResetRequired bool               `json:"password_reset_required"`
+	AvatarKey             pgtype.Text        `json:"avatar_key"`
+	DeletedAt             pgtype.Timestamptz `json:"deleted_at"`
+	PurgingAt             pgtype.Timestamptz `json:"purging_at"`
+}
+
+type UserIdentity struct {
+	Provider    string             `json:"provider"`
+	Subject     string             `json:"subject"`
+	UserID      string             `json:"user_id"`
+	Email       string             `json:"email"`
+	LastLoginAt pgtype.Timestamptz `json:"last_login_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type UserTotp struct {
+	UserID          string             `json:"user_id"`
+	SecretEncrypted []byte             `json:"secret_encrypted"`
+	ConfirmedAt     pgtype.Timestamptz `json:"confirmed_at"`
+	LastUsedStep    int64              `json:"last_used_step"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
 }
@@ -0,0 +1,43 @@
+-- name: CreateOIDCLoginState :exec
+INSERT INTO oidc_login_states (
+    state_hash,
+    provider,
+    nonce,
+    code_verifier,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: ConsumeOIDCLoginState :one
+-- Deletes and returns the state so a callback can be completed only once.
+DELETE FROM oidc_login_states
+WHERE state_hash = $1
+RETURNING *;
+
+-- name: DeleteExpiredOIDCLoginStates :execrows
+DELETE FROM oidc_login_states
+WHERE expires_at < now();
+
+-- name: GetUserByIdentity :one
+SELECT u.id, u.name, u.email, u.password, u.profile_picture, u.created_at, u.updated_at, u.token_version, u.email_verified_at, u.role, u.locked_at, u.password_reset_required, u.avatar_key, u.deleted_at
+FROM users u
+JOIN user_identities i ON i.user_id = u.id
+WHERE i.provider = $1 AND i.subject = $2
+LIMIT 1;
+
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (
+    provider,
+    subject,
+    user_id,
+    email
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: TouchUserIdentity :exec
+UPDATE user_identities
+SET last_login_at = now(),
+    email = $3
+WHERE provider = $1 AND subject = $2;
@@ -0,0 +1,23 @@
+-- name: CreatePasswordResetToken :exec
+INSERT INTO password_reset_tokens (
+    id,
+    user_id,
+    token_hash,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: GetPasswordResetTokenByHashForUpdate :one
+-- Locks the row so two concurrent resets with the same token cannot both succeed.
+SELECT *
+FROM password_reset_tokens
+WHERE token_hash = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: ConsumeUserPasswordResetTokens :exec
+-- Marks every outstanding reset token of the user as used.
+UPDATE password_reset_tokens
+SET used_at = now()
+WHERE user_id = $1 AND used_at IS NULL;
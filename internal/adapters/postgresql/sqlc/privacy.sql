@@ -0,0 +1,127 @@
+-- name: CreateDataExport :one
+INSERT INTO data_exports (
+    id,
+    user_id
+) VALUES (
+    $1, $2
+)
+RETURNING *;
+
+-- name: GetOpenDataExport :one
+-- The user's export that is still being built, if any.
+SELECT *
+FROM data_exports
+WHERE user_id = $1 AND status IN ('pending', 'processing')
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: CountDataExportsSince :one
+SELECT count(*)::int AS requested
+FROM data_exports
+WHERE user_id = $1 AND created_at >= $2;
+
+-- name: ListDataExports :many
+SELECT *
+FROM data_exports
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: ClaimDataExport :one
+-- Takes the oldest pending export, or one whose worker has not finished it
+-- since stale_before. SKIP LOCKED lets several replicas claim side by side.
+UPDATE data_exports
+SET status = 'processing',
+    started_at = now()
+WHERE id = (
+    SELECT id
+    FROM data_exports
+    WHERE status = 'pending'
+       OR (status = 'processing' AND started_at < @stale_before)
+    ORDER BY created_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteDataExport :exec
+UPDATE data_exports
+SET status = 'ready',
+    blob_key = $2,
+    token_hash = $3,
+    size_bytes = $4,
+    expires_at = $5,
+    completed_at = now()
+WHERE id = $1;
+
+-- name: FailDataExport :exec
+UPDATE data_exports
+SET status = 'failed',
+    completed_at = now()
+WHERE id = $1;
+
+-- name: GetDataExportByTokenHash :one
+SELECT *
+FROM data_exports
+WHERE token_hash = $1 AND status = 'ready' AND expires_at > now()
+LIMIT 1;
+
+-- name: ListExpiredDataExports :many
+SELECT *
+FROM data_exports
+WHERE status = 'ready' AND expires_at <= now()
+ORDER BY expires_at
+LIMIT $1;
+
+-- name: ExpireDataExport :exec
+-- The file is gone, so the download link can never work again.
+UPDATE data_exports
+SET status = 'expired',
+    blob_key = NULL,
+    token_hash = NULL
+WHERE id = $1;
+
+-- name: ListTransactionsForExport :many
+SELECT *
+FROM transactions
+WHERE user_id = $1
+ORDER BY occurred_at, id;
+
+-- name: ListAccountsForExport :many
+SELECT *
+FROM accounts
+WHERE user_id = $1
+ORDER BY created_at, id;
+
+-- name: ListJournalEntriesForExport :many
+SELECT *
+FROM journal_entries
+WHERE user_id = $1
+ORDER BY occurred_at, id;
+
+-- name: ListPostingsForExport :many
+SELECT p.id, p.journal_entry_id, p.line, p.account_id, p.amount, p.currency, p.created_at
+FROM postings p
+JOIN journal_entries e ON e.id = p.journal_entry_id
+WHERE e.user_id = $1
+ORDER BY e.occurred_at, e.id, p.line;
+
+-- name: ListUsersDueForPurge :many
+SELECT id, email
+FROM users
+WHERE deleted_at <= $1
+ORDER BY deleted_at
+LIMIT $2;
+
+-- name: MarkUserPurging :execrows
+-- Claims a deleted account for purging, unless it was restored or deleted
+-- after $2 meanwhile. RestoreUser refuses claimed accounts.
+UPDATE users
+SET purging_at = COALESCE(purging_at, now())
+WHERE id = $1 AND deleted_at <= $2;
+
+-- name: PurgeUser :execrows
+-- Every row the user owns references users with ON DELETE CASCADE. Only
+-- accounts claimed by MarkUserPurging are deleted.
+DELETE FROM users
+WHERE id = $1 AND purging_at IS NOT NULL;
@@ -6,12 +6,156 @@ package repo
 
 import (
 	"context"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/money"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	// Takes the oldest pending export, or one whose worker has not finished it
+	// since stale_before. SKIP LOCKED lets several replicas claim side by side.
+	ClaimDataExport(ctx context.Context, staleBefore pgtype.Timestamptz) (DataExport, error)
+	ClearLoginFailures(ctx context.Context, key string) error
+	CompleteDataExport(ctx context.Context, arg CompleteDataExportParams) error
+	ConfirmUserTOTP(ctx context.Context, arg ConfirmUserTOTPParams) error
+	// Deletes and returns the state so a callback can be completed only once.
+	ConsumeOIDCLoginState(ctx context.Context, stateHash string) (OidcLoginState, error)
+	// Marks every outstanding email change token of the user as used.
+	ConsumeUserEmailChangeTokens(ctx context.Context, userID string) error
+	// Marks every outstanding verification token of the user as used.
+	ConsumeUserEmailVerificationTokens(ctx context.Context, userID string) error
+	// Marks every outstanding magic link of the user as used.
+	ConsumeUserMagicLinkTokens(ctx context.Context, userID string) error
+	// Marks every outstanding reset token of the user as used.
+	ConsumeUserPasswordResetTokens(ctx context.Context, userID string) error
+	CountDataExportsSince(ctx context.Context, arg CountDataExportsSinceParams) (int32, error)
+	CountEmailChangeTokensSince(ctx context.Context, arg CountEmailChangeTokensSinceParams) (int32, error)
+	CountMagicLinkTokensSince(ctx context.Context, arg CountMagicLinkTokensSinceParams) (int32, error)
+	CountUsers(ctx context.Context, search string) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAdminAuditEntry(ctx context.Context, arg CreateAdminAuditEntryParams) error
+	CreateDataExport(ctx context.Context, arg CreateDataExportParams) (DataExport, error)
+	CreateEmailChangeToken(ctx context.Context, arg CreateEmailChangeTokenParams) error
+	CreateEmailVerificationToken(ctx context.Context, arg CreateEmailVerificationTokenParams) error
+	CreateJournalEntry(ctx context.Context, arg CreateJournalEntryParams) (JournalEntry, error)
+	CreateMFAChallenge(ctx context.Context, arg CreateMFAChallengeParams) error
+	CreateMagicLinkToken(ctx context.Context, arg CreateMagicLinkTokenParams) error
+	CreateOIDCLoginState(ctx context.Context, arg CreateOIDCLoginStateParams) error
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) error
+	CreatePosting(ctx context.Context, arg CreatePostingParams) (Posting, error)
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) error
+	CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) error
+	DeleteExpiredOIDCLoginStates(ctx context.Context) (int64, error)
+	DeleteExpiredRevokedTokens(ctx context.Context) (int64, error)
+	DeleteStaleLoginThrottles(ctx context.Context, lastFailedAt pgtype.Timestamptz) (int64, error)
+	DeleteTransaction(ctx context.Context, arg DeleteTransactionParams) (int64, error)
+	DeleteUserAPIKey(ctx context.Context, arg DeleteUserAPIKeyParams) (int64, error)
+	DeleteUserRecoveryCodes(ctx context.Context, userID string) error
+	// The file is gone, so the download link can never work again.
+	ExpireDataExport(ctx context.Context, id string) error
+	FailDataExport(ctx context.Context, id string) error
+	// Keys of locked or deleted accounts are treated as unknown.
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (ApiKey, error)
+	GetAccount(ctx context.Context, arg GetAccountParams) (Account, error)
+	GetAccountBalance(ctx context.Context, accountID string) (money.Decimal, error)
+	GetDataExportByTokenHash(ctx context.Context, tokenHash pgtype.Text) (DataExport, error)
+	GetEmailChangeTokenByHashForUpdate(ctx context.Context, tokenHash string) (EmailChangeToken, error)
+	// How many verification emails the user was sent since $2, and when the last one went out.
+	GetEmailVerificationSendStats(ctx context.Context, arg GetEmailVerificationSendStatsParams) (GetEmailVerificationSendStatsRow, error)
+	GetEmailVerificationTokenByHashForUpdate(ctx context.Context, tokenHash string) (EmailVerificationToken, error)
+	GetJournalEntry(ctx context.Context, arg GetJournalEntryParams) (JournalEntry, error)
+	// Latest lockout among the given keys; NULL when none is locked.
+	GetLoginLockedUntil(ctx context.Context, keys []string) (pgtype.Timestamptz, error)
+	GetMFAChallengeByHashForUpdate(ctx context.Context, tokenHash string) (MfaChallenge, error)
+	// Locks the row so the same link cannot log in twice concurrently.
+	GetMagicLinkTokenByHashForUpdate(ctx context.Context, tokenHash string) (MagicLinkToken, error)
+	// The user's export that is still being built, if any.
+	GetOpenDataExport(ctx context.Context, userID string) (DataExport, error)
+	// Locks the row so two concurrent resets with the same token cannot both succeed.
+	GetPasswordResetTokenByHashForUpdate(ctx context.Context, tokenHash string) (PasswordResetToken, error)
+	// Locks the row so concurrent refreshes with the same token serialize.
+	GetRefreshTokenByHashForUpdate(ctx context.Context, tokenHash string) (RefreshToken, error)
+	GetTransaction(ctx context.Context, arg GetTransactionParams) (Transaction, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id string) (User, error)
+	GetUserByIdentity(ctx context.Context, arg GetUserByIdentityParams) (User, error)
+	GetUserEmailVerifiedAt(ctx context.Context, id string) (pgtype.Timestamptz, error)
+	GetUserTOTP(ctx context.Context, userID string) (UserTotp, error)
+	GetUserTOTPForUpdate(ctx context.Context, userID string) (UserTotp, error)
+	GetUserTokenVersion(ctx context.Context, id string) (int32, error)
+	IncrementMFAChallengeAttempts(ctx context.Context, id string) error
+	IncrementUserTokenVersion(ctx context.Context, id string) (int32, error)
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+	ListAccounts(ctx context.Context, userID string) ([]Account, error)
+	ListAccountsForExport(ctx context.Context, userID string) ([]Account, error)
+	ListAdminAuditEntries(ctx context.Context, arg ListAdminAuditEntriesParams) ([]AdminAuditLog, error)
+	ListDataExports(ctx context.Context, arg ListDataExportsParams) ([]DataExport, error)
+	ListExpiredDataExports(ctx context.Context, limit int32) ([]DataExport, error)
+	ListJournalEntries(ctx context.Context, arg ListJournalEntriesParams) ([]JournalEntry, error)
+	ListJournalEntriesForExport(ctx context.Context, userID string) ([]JournalEntry, error)
+	ListPostingsByJournalEntries(ctx context.Context, journalEntryIds []string) ([]Posting, error)
+	ListPostingsByJournalEntry(ctx context.Context, journalEntryID string) ([]Posting, error)
+	ListPostingsForExport(ctx context.Context, userID string) ([]Posting, error)
+	ListTransactions(ctx context.Context, arg ListTransactionsParams) ([]Transaction, error)
+	ListTransactionsForExport(ctx context.Context, userID string) ([]Transaction, error)
+	ListUserAPIKeys(ctx context.Context, userID string) ([]ApiKey, error)
+	ListUserSessions(ctx context.Context, arg ListUserSessionsParams) ([]Session, error)
+	// An empty search matches every user; otherwise name or email must contain it.
+	// login_locked_until is the brute-force lockout, if one is in force.
+	ListUsers(ctx context.Context, arg ListUsersParams) ([]ListUsersRow, error)
+	ListUsersDueForPurge(ctx context.Context, arg ListUsersDueForPurgeParams) ([]ListUsersDueForPurgeRow, error)
+	LockLoginKey(ctx context.Context, arg LockLoginKeyParams) error
+	LockUser(ctx context.Context, id string) (int64, error)
+	MarkMFAChallengeUsed(ctx context.Context, id string) error
+	MarkRefreshTokenUsed(ctx context.Context, id string) error
+	MarkUserEmailVerified(ctx context.Context, id string) error
+	// Claims a deleted account for purging, unless it was restored or deleted
+	// after $2 meanwhile. RestoreUser refuses claimed accounts.
+	MarkUserPurging(ctx context.Context, arg MarkUserPurgingParams) (int64, error)
+	// Every row the user owns references users with ON DELETE CASCADE. Only
+	// accounts claimed by MarkUserPurging are deleted.
+	PurgeUser(ctx context.Context, id string) (int64, error)
+	// Counts a failure, starting over when the previous one is older than reset_before.
+	RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (int32, error)
+	// Records a token refresh and the address it came from.
+	RefreshSession(ctx context.Context, arg RefreshSessionParams) error
+	RequireUserPasswordReset(ctx context.Context, id string) (int64, error)
+	// An account whose purge has started keeps its deletion mark; purging_at is
+	// returned so the caller can tell.
+	RestoreUser(ctx context.Context, id string) (pgtype.Timestamptz, error)
+	RevokeAccessToken(ctx context.Context, arg RevokeAccessTokenParams) error
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string) error
+	RevokeSession(ctx context.Context, id string) error
+	RevokeUserRefreshTokens(ctx context.Context, userID string) error
+	RevokeUserSession(ctx context.Context, arg RevokeUserSessionParams) (int64, error)
+	RevokeUserSessions(ctx context.Context, userID string) error
+	// Returns the replaced avatar so its files can be deleted.
+	SetUserAvatar(ctx context.Context, arg SetUserAvatarParams) (pgtype.Text, error)
+	// Deleting twice keeps the first deletion time, so the grace period is not extended.
+	SoftDeleteUser(ctx context.Context, id string) (pgtype.Timestamptz, error)
+	// Records use at most once a minute so busy scripts do not write on every request.
+	TouchAPIKey(ctx context.Context, id string) error
+	// Records activity on a live session; no rows means it was revoked or never existed.
+	TouchSession(ctx context.Context, id string) (int64, error)
+	TouchUserIdentity(ctx context.Context, arg TouchUserIdentityParams) error
+	UnlockUser(ctx context.Context, id string) (int64, error)
+	// Only non-null arguments overwrite the stored value (PATCH semantics).
+	UpdateTransaction(ctx context.Context, arg UpdateTransactionParams) (Transaction, error)
+	// The new address was confirmed through the emailed link, so it counts as verified.
+	UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) error
+	// Also clears a reset demanded by an admin.
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	// Only non-null arguments overwrite the stored value (PATCH semantics).
+	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
+	UpdateUserTOTPLastUsedStep(ctx context.Context, arg UpdateUserTOTPLastUsedStepParams) error
+	// Starts (or restarts) enrollment. Never touches a confirmed enrollment.
+	UpsertPendingUserTOTP(ctx context.Context, arg UpsertPendingUserTOTPParams) error
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -1,11 +1,11 @@
 -- name: GetUserByEmail :one
-SELECT id, name, email, password, profile_picture, created_at, updated_at
+SELECT id, name, email, password, profile_picture, created_at, updated_at, token_version, email_verified_at, role, locked_at, password_reset_required, avatar_key, deleted_at
 FROM users
 WHERE email = $1
 LIMIT 1;
 
 -- name: GetUserByID :one
-SELECT id, name, email, password, profile_picture, created_at, updated_at
+SELECT id, name, email, password, profile_picture, created_at, updated_at, token_version, email_verified_at, role, locked_at, password_reset_required, avatar_key, deleted_at
 FROM users
 WHERE id = $1
 LIMIT 1;
@@ -20,4 +20,97 @@ INSERT INTO users (
 ) VALUES (
     $1, $2, $3, $4, $5
 )
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: GetUserTokenVersion :one
+SELECT token_version
+FROM users
+WHERE id = $1;
+
+-- name: IncrementUserTokenVersion :one
+UPDATE users
+SET token_version = token_version + 1,
+    updated_at = now()
+WHERE id = $1
+RETURNING token_version;
+
+-- name: UpdateUserPassword :exec
+-- Also clears a reset demanded by an admin.
+UPDATE users
+SET password = $2,
+    password_reset_required = false,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: MarkUserEmailVerified :exec
+UPDATE users
+SET email_verified_at = COALESCE(email_verified_at, now()),
+    updated_at = now()
+WHERE id = $1;
+
+-- name: GetUserEmailVerifiedAt :one
+SELECT email_verified_at
+FROM users
+WHERE id = $1;
+
+-- name: LockUser :execrows
+UPDATE users
+SET locked_at = COALESCE(locked_at, now()),
+    updated_at = now()
+WHERE id = $1;
+
+-- name: UnlockUser :execrows
+UPDATE users
+SET locked_at = NULL,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: RequireUserPasswordReset :execrows
+UPDATE users
+SET password_reset_required = true,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: UpdateUserProfile :one
+-- Only non-null arguments overwrite the stored value (PATCH semantics).
+UPDATE users
+SET
+    name            = COALESCE(sqlc.narg('name')::text, name),
+    profile_picture = COALESCE(sqlc.narg('profile_picture')::text, profile_picture),
+    updated_at      = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: UpdateUserEmail :exec
+-- The new address was confirmed through the emailed link, so it counts as verified.
+UPDATE users
+SET email = $2,
+    email_verified_at = now(),
+    updated_at = now()
+WHERE id = $1;
+
+-- name: SetUserAvatar :one
+-- Returns the replaced avatar so its files can be deleted.
+UPDATE users u
+SET avatar_key = $2,
+    updated_at = now()
+FROM (SELECT id, avatar_key FROM users WHERE id = $1 FOR UPDATE) old
+WHERE u.id = old.id
+RETURNING old.avatar_key AS previous_avatar_key;
+
+-- name: SoftDeleteUser :one
+-- Deleting twice keeps the first deletion time, so the grace period is not extended.
+UPDATE users
+SET deleted_at = COALESCE(deleted_at, now()),
+    updated_at = now()
+WHERE id = $1
+RETURNING deleted_at;
+
+-- name: RestoreUser :one
+-- An account whose purge has started keeps its deletion mark; purging_at is
+-- returned so the caller can tell.
+UPDATE users
+SET deleted_at = CASE WHEN purging_at IS NULL THEN NULL ELSE deleted_at END,
+    updated_at = now()
+WHERE id = $1
+RETURNING purging_at;
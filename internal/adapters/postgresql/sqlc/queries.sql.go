@@ -21,7 +21,7 @@ INSERT INTO users (
 ) VALUES (
     $1, $2, $3, $4, $5
 )
-RETURNING id, name, email, password, profile_picture, created_at, updated_at
+RETURNING id, name, email, password, profile_picture, created_at, updated_at, token_version, email_verified_at, role, locked_at, password_reset_required, avatar_key, deleted_at, purging_at
 `
 
 type CreateUserParams struct {
@@ -49,12 +49,20 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.ProfilePicture,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TokenVersion,
+		&i.EmailVerifiedAt,
+		&i.Role,
+		&i.LockedAt,
+		&i.PasswordResetRequired,
+		&i.AvatarKey,
+		&i.DeletedAt,
+		&i.PurgingAt,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, name, email, password, profile_picture, created_at, updated_at
+SELECT id, name, email, password, profile_picture, created_at, updated_at, token_version, email_verified_at, role, locked_at, password_reset_required, avatar_key, deleted_at, purging_at
 FROM users
 WHERE email = $1
 LIMIT 1
@@ -71,12 +79,20 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.ProfilePicture,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TokenVersion,
+		&i.EmailVerifiedAt,
+		&i.Role,
+		&i.LockedAt,
+		&i.PasswordResetRequired,
+		&i.AvatarKey,
+		&i.DeletedAt,
+		&i.PurgingAt,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, name, email, password, profile_picture, created_at, updated_at
+SELECT id, name, email, password, profile_picture, created_at, updated_at, token_version, email_verified_at, role, locked_at, password_reset_required, avatar_key, deleted_at, purging_at
 FROM users
 WHERE id = $1
 LIMIT 1
@@ -93,6 +109,245 @@ func (q *Queries) GetUserByID(ctx context.Context, id string) (User, error) {
 		&i.ProfilePicture,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TokenVersion,
+		&i.EmailVerifiedAt,
+		&i.Role,
+		&i.LockedAt,
+		&i.PasswordResetRequired,
+		&i.AvatarKey,
+		&i.DeletedAt,
+		&i.PurgingAt,
+	)
+	return i, err
+}
+
+const getUserEmailVerifiedAt = `-- name: GetUserEmailVerifiedAt :one
+SELECT email_verified_at
+FROM users
+WHERE id = $1
+`
+
+func (q *Queries) GetUserEmailVerifiedAt(ctx context.Context, id string) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, getUserEmailVerifiedAt, id)
+	var emailVerifiedAt pgtype.Timestamptz
+	err := row.Scan(&emailVerifiedAt)
+	return emailVerifiedAt, err
+}
+
+const getUserTokenVersion = `-- name: GetUserTokenVersion :one
+SELECT token_version
+FROM users
+WHERE id = $1
+`
+
+func (q *Queries) GetUserTokenVersion(ctx context.Context, id string) (int32, error) {
+	row := q.db.QueryRow(ctx, getUserTokenVersion, id)
+	var tokenVersion int32
+	err := row.Scan(&tokenVersion)
+	return tokenVersion, err
+}
+
+const incrementUserTokenVersion = `-- name: IncrementUserTokenVersion :one
+UPDATE users
+SET token_version = token_version + 1,
+    updated_at = now()
+WHERE id = $1
+RETURNING token_version
+`
+
+func (q *Queries) IncrementUserTokenVersion(ctx context.Context, id string) (int32, error) {
+	row := q.db.QueryRow(ctx, incrementUserTokenVersion, id)
+	var tokenVersion int32
+	err := row.Scan(&tokenVersion)
+	return tokenVersion, err
+}
+
+const lockUser = `-- name: LockUser :execrows
+UPDATE users
+SET locked_at = COALESCE(locked_at, now()),
+    updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) LockUser(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.Exec(ctx, lockUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markUserEmailVerified = `-- name: MarkUserEmailVerified :exec
+UPDATE users
+SET email_verified_at = COALESCE(email_verified_at, now()),
+    updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) MarkUserEmailVerified(ctx context.Context, id string) error {
+	_, err := q.db.Exec(ctx, markUserEmailVerified, id)
+	return err
+}
+
+const requireUserPasswordReset = `-- name: RequireUserPasswordReset :execrows
+UPDATE users
+SET password_reset_required = true,
+    updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) RequireUserPasswordReset(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.Exec(ctx, requireUserPasswordReset, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = CASE WHEN purging_at IS NULL THEN NULL ELSE deleted_at END,
+    updated_at = now()
+WHERE id = $1
+RETURNING purging_at
+`
+
+// An account whose purge has started keeps its deletion mark; purging_at is
+// returned so the caller can tell.
+func (q *Queries) RestoreUser(ctx context.Context, id string) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, restoreUser, id)
+	var purgingAt pgtype.Timestamptz
+	err := row.Scan(&purgingAt)
+	return purgingAt, err
+}
+
+const setUserAvatar = `-- name: SetUserAvatar :one
+UPDATE users u
+SET avatar_key = $2,
+    updated_at = now()
+FROM (SELECT id, avatar_key FROM users WHERE id = $1 FOR UPDATE) old
+WHERE u.id = old.id
+RETURNING old.avatar_key AS previous_avatar_key
+`
+
+type SetUserAvatarParams struct {
+	ID        string      `json:"id"`
+	AvatarKey pgtype.Text `json:"avatar_key"`
+}
+
+// Returns the replaced avatar so its files can be deleted.
+func (q *Queries) SetUserAvatar(ctx context.Context, arg SetUserAvatarParams) (pgtype.Text, error) {
+	row := q.db.QueryRow(ctx, setUserAvatar, arg.ID, arg.AvatarKey)
+	var previousAvatarKey pgtype.Text
+	err := row.Scan(&previousAvatarKey)
+	return previousAvatarKey, err
+}
+
+const softDeleteUser = `-- name: SoftDeleteUser :one
+UPDATE users
+SET deleted_at = COALESCE(deleted_at, now()),
+    updated_at = now()
+WHERE id = $1
+RETURNING deleted_at
+`
+
+// Deleting twice keeps the first deletion time, so the grace period is not extended.
+func (q *Queries) SoftDeleteUser(ctx context.Context, id string) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, softDeleteUser, id)
+	var deletedAt pgtype.Timestamptz
+	err := row.Scan(&deletedAt)
+	return deletedAt, err
+}
+
+const unlockUser = `-- name: UnlockUser :execrows
+UPDATE users
+SET locked_at = NULL,
+    updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) UnlockUser(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.Exec(ctx, unlockUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateUserEmail = `-- name: UpdateUserEmail :exec
+UPDATE users
+SET email = $2,
+    email_verified_at = now(),
+    updated_at = now()
+WHERE id = $1
+`
+
+type UpdateUserEmailParams struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+}
+
+// The new address was confirmed through the emailed link, so it counts as verified.
+func (q *Queries) UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) error {
+	_, err := q.db.Exec(ctx, updateUserEmail, arg.ID, arg.Email)
+	return err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users
+SET password = $2,
+    password_reset_required = false,
+    updated_at = now()
+WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
+	ID       string `json:"id"`
+	Password string `json:"password"`
+}
+
+// Also clears a reset demanded by an admin.
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, updateUserPassword, arg.ID, arg.Password)
+	return err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+SET
+    name            = COALESCE($1::text, name),
+    profile_picture = COALESCE($2::text, profile_picture),
+    updated_at      = now()
+WHERE id = $3
+RETURNING id, name, email, password, profile_picture, created_at, updated_at, token_version, email_verified_at, role, locked_at, password_reset_required, avatar_key, deleted_at, purging_at
+`
+
+type UpdateUserProfileParams struct {
+	Name           pgtype.Text `json:"name"`
+	ProfilePicture pgtype.Text `json:"profile_picture"`
+	ID             string      `json:"id"`
+}
+
+// Only non-null arguments overwrite the stored value (PATCH semantics).
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserProfile, arg.Name, arg.ProfilePicture, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Password,
+		&i.ProfilePicture,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TokenVersion,
+		&i.EmailVerifiedAt,
+		&i.Role,
+		&i.LockedAt,
+		&i.PasswordResetRequired,
+		&i.AvatarKey,
+		&i.DeletedAt,
+		&i.PurgingAt,
 	)
 	return i, err
 }
@@ -0,0 +1,18 @@
+-- name: RevokeAccessToken :exec
+INSERT INTO revoked_tokens (
+    jti,
+    user_id,
+    expires_at
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (jti) DO NOTHING;
+
+-- name: IsAccessTokenRevoked :one
+SELECT EXISTS (
+    SELECT 1 FROM revoked_tokens WHERE jti = $1
+) AS revoked;
+
+-- name: DeleteExpiredRevokedTokens :execrows
+DELETE FROM revoked_tokens
+WHERE expires_at < now();
@@ -0,0 +1,44 @@
+-- name: CreateSession :exec
+INSERT INTO sessions (
+    id,
+    user_id,
+    user_agent,
+    ip,
+    device_name
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: TouchSession :execrows
+-- Records activity on a live session; no rows means it was revoked or never existed.
+UPDATE sessions
+SET last_seen_at = now()
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RefreshSession :exec
+-- Records a token refresh and the address it came from.
+UPDATE sessions
+SET last_seen_at = now(),
+    ip = $2
+WHERE id = $1;
+
+-- name: ListUserSessions :many
+SELECT *
+FROM sessions
+WHERE user_id = $1 AND revoked_at IS NULL AND last_seen_at > $2
+ORDER BY last_seen_at DESC;
+
+-- name: RevokeSession :exec
+UPDATE sessions
+SET revoked_at = now()
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserSession :execrows
+UPDATE sessions
+SET revoked_at = now()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: RevokeUserSessions :exec
+UPDATE sessions
+SET revoked_at = now()
+WHERE user_id = $1 AND revoked_at IS NULL;
@@ -0,0 +1,45 @@
+-- name: CreateTransaction :one
+INSERT INTO transactions (
+    id,
+    user_id,
+    amount,
+    type,
+    category,
+    description,
+    occurred_at,
+    currency
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING *;
+
+-- name: GetTransaction :one
+SELECT *
+FROM transactions
+WHERE id = $1 AND user_id = $2
+LIMIT 1;
+
+-- name: ListTransactions :many
+SELECT *
+FROM transactions
+WHERE user_id = $1
+ORDER BY occurred_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: UpdateTransaction :one
+-- Only non-null arguments overwrite the stored value (PATCH semantics).
+UPDATE transactions
+SET
+    amount      = COALESCE(sqlc.narg('amount')::numeric, amount),
+    currency    = COALESCE(sqlc.narg('currency')::text, currency),
+    type        = COALESCE(sqlc.narg('type')::text, type),
+    category    = COALESCE(sqlc.narg('category')::text, category),
+    description = COALESCE(sqlc.narg('description')::text, description),
+    occurred_at = COALESCE(sqlc.narg('occurred_at')::timestamptz, occurred_at),
+    updated_at  = now()
+WHERE id = sqlc.arg('id') AND user_id = sqlc.arg('user_id')
+RETURNING *;
+
+-- name: DeleteTransaction :execrows
+DELETE FROM transactions
+WHERE id = $1 AND user_id = $2;
@@ -0,0 +1,80 @@
+-- name: UpsertPendingUserTOTP :exec
+-- Starts (or restarts) enrollment. Never touches a confirmed enrollment.
+INSERT INTO user_totp (
+    user_id,
+    secret_encrypted
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (user_id) DO UPDATE
+SET secret_encrypted = EXCLUDED.secret_encrypted,
+    last_used_step = 0,
+    created_at = now()
+WHERE user_totp.confirmed_at IS NULL;
+
+-- name: GetUserTOTP :one
+SELECT *
+FROM user_totp
+WHERE user_id = $1;
+
+-- name: GetUserTOTPForUpdate :one
+SELECT *
+FROM user_totp
+WHERE user_id = $1
+FOR UPDATE;
+
+-- name: ConfirmUserTOTP :exec
+UPDATE user_totp
+SET confirmed_at = now(),
+    last_used_step = $2
+WHERE user_id = $1;
+
+-- name: UpdateUserTOTPLastUsedStep :exec
+UPDATE user_totp
+SET last_used_step = $2
+WHERE user_id = $1;
+
+-- name: DeleteUserRecoveryCodes :exec
+DELETE FROM recovery_codes
+WHERE user_id = $1;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO recovery_codes (
+    id,
+    user_id,
+    code_hash
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: UseRecoveryCode :execrows
+UPDATE recovery_codes
+SET used_at = now()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: CreateMFAChallenge :exec
+INSERT INTO mfa_challenges (
+    id,
+    user_id,
+    token_hash,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: GetMFAChallengeByHashForUpdate :one
+SELECT *
+FROM mfa_challenges
+WHERE token_hash = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: IncrementMFAChallengeAttempts :exec
+UPDATE mfa_challenges
+SET attempts = attempts + 1
+WHERE id = $1;
+
+-- name: MarkMFAChallengeUsed :exec
+UPDATE mfa_challenges
+SET used_at = now()
+WHERE id = $1;
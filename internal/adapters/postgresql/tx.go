@@ -0,0 +1,265 @@
+// Package postgresql holds the Postgres plumbing shared by every repository:
+// the unit-of-work transaction manager, ambient-transaction lookup and the
+// schema migrator.
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	repo "github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql/sqlc"
+)
+
+const (
+	defaultMaxRetries = 3
+	defaultBackoff    = 20 * time.Millisecond
+)
+
+// Beginner opens top-level transactions. *pgxpool.Pool and *pgx.Conn satisfy it.
+type Beginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+// ErrTxOptionsConflict is returned by a nested RunInTx whose isolation level
+// or access mode differs from the outer transaction's, which a savepoint
+// cannot change.
+var ErrTxOptionsConflict = errors.New("nested transaction options conflict with the outer transaction")
+
+// txKey is the context key under which the ambientTx is stored.
+type txKey struct{}
+
+// ambientTx is the transaction, or savepoint, carried by ctx and the options
+// of the outermost transaction.
+type ambientTx struct {
+	tx  pgx.Tx
+	cfg txConfig
+}
+
+// TxOption customises a TxManager or a single RunInTx call.
+type TxOption func(*txConfig)
+
+type txConfig struct {
+	isolation  pgx.TxIsoLevel
+	access     pgx.TxAccessMode // empty means read-write
+	maxRetries int
+	backoff    time.Duration
+}
+
+// WithIsolation sets the isolation level (pgx.ReadCommitted, pgx.RepeatableRead, pgx.Serializable, …).
+func WithIsolation(level pgx.TxIsoLevel) TxOption {
+	return func(c *txConfig) { c.isolation = level }
+}
+
+// WithReadOnly starts the transaction READ ONLY.
+func WithReadOnly() TxOption {
+	return func(c *txConfig) { c.access = pgx.ReadOnly }
+}
+
+// WithReadWrite starts the transaction READ WRITE, the default. Nested, it
+// asserts that the outer transaction can write.
+func WithReadWrite() TxOption {
+	return func(c *txConfig) { c.access = pgx.ReadWrite }
+}
+
+// WithMaxRetries sets how many times a transaction is re-run after a
+// serialization failure or deadlock. Zero disables retries.
+func WithMaxRetries(n int) TxOption {
+	return func(c *txConfig) { c.maxRetries = n }
+}
+
+// WithBackoff sets the base delay between retries; it doubles on each attempt.
+func WithBackoff(d time.Duration) TxOption {
+	return func(c *txConfig) { c.backoff = d }
+}
+
+// ParseIsolation maps a config string such as "serializable" or
+// "repeatable read" onto a pgx.TxIsoLevel. An empty string means the
+// server default.
+func ParseIsolation(s string) (pgx.TxIsoLevel, error) {
+	switch s {
+	case "":
+		return "", nil
+	case "read committed", "read_committed":
+		return pgx.ReadCommitted, nil
+	case "repeatable read", "repeatable_read":
+		return pgx.RepeatableRead, nil
+	case "serializable":
+		return pgx.Serializable, nil
+	default:
+		return "", fmt.Errorf("unknown isolation level %q", s)
+	}
+}
+
+// TxManager runs units of work inside a database transaction.
+//
+// Calls nest: RunInTx inside another RunInTx (detected through ctx) opens a
+// SAVEPOINT instead of a new transaction, so a failing inner unit rolls back
+// only its own work. Only the outermost call retries, and a nested call runs
+// with the outer isolation level and access mode.
+type TxManager struct {
+	db      Beginner
+	queries *repo.Queries
+	config  txConfig
+}
+
+// NewTxManager constructs a TxManager. queries is the non-transactional
+// Queries that each unit of work's Queries is derived from via WithTx.
+func NewTxManager(db Beginner, queries *repo.Queries, opts ...TxOption) *TxManager {
+	cfg := txConfig{maxRetries: defaultMaxRetries, backoff: defaultBackoff}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &TxManager{db: db, queries: queries, config: cfg}
+}
+
+// RunInTx runs fn in a transaction and commits if it returns nil. The ctx
+// passed to fn carries the transaction, so repositories called with it join
+// the same unit of work (see QueriesFromContext). fn may be invoked more
+// than once when the transaction is retried and must not have side effects
+// outside the database.
+//
+// Nested in another RunInTx, the retry options are ignored, and asking for a
+// different isolation level or access mode than the outer transaction's
+// fails with ErrTxOptionsConflict.
+func (m *TxManager) RunInTx(ctx context.Context, fn func(ctx context.Context, q *repo.Queries) error, opts ...TxOption) error {
+	if outer, ok := ctx.Value(txKey{}).(ambientTx); ok {
+		if err := checkNested(outer.cfg, opts); err != nil {
+			return err
+		}
+		return m.runNested(ctx, outer, fn)
+	}
+
+	cfg := m.config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	backoff := cfg.backoff
+	for attempt := 0; ; attempt++ {
+		err := m.runOnce(ctx, cfg, fn)
+		if err == nil || !isRetryable(err) || attempt >= cfg.maxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// Atomic is RunInTx for callers that must not see sqlc types — services
+// coordinating several repositories.
+func (m *TxManager) Atomic(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.RunInTx(ctx, func(ctx context.Context, _ *repo.Queries) error {
+		return fn(ctx)
+	})
+}
+
+// Snapshot is Atomic in a READ ONLY, REPEATABLE READ transaction, so every
+// read fn makes sees the database as of the same moment.
+func (m *TxManager) Snapshot(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.RunInTx(ctx, func(ctx context.Context, _ *repo.Queries) error {
+		return fn(ctx)
+	}, WithIsolation(pgx.RepeatableRead), WithReadOnly())
+}
+
+func (m *TxManager) runOnce(ctx context.Context, cfg txConfig, fn func(ctx context.Context, q *repo.Queries) error) (err error) {
+	tx, err := m.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: cfg.isolation, AccessMode: cfg.accessMode()})
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(ctx)
+			panic(p)
+		}
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, ambientTx{tx: tx, cfg: cfg}), m.queries.WithTx(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// checkNested returns ErrTxOptionsConflict if opts ask for a mode the outer
+// transaction, configured by outer, does not run in.
+func checkNested(outer txConfig, opts []TxOption) error {
+	var requested txConfig
+	for _, opt := range opts {
+		opt(&requested)
+	}
+
+	if requested.isolation != "" && requested.isolation != outer.isolation {
+		current := string(outer.isolation)
+		if current == "" {
+			current = "the server default"
+		}
+		return fmt.Errorf("%w: %s requested inside %s", ErrTxOptionsConflict, requested.isolation, current)
+	}
+	if requested.access != "" && requested.access != outer.accessMode() {
+		return fmt.Errorf("%w: %s requested inside a %s transaction", ErrTxOptionsConflict, requested.access, outer.accessMode())
+	}
+	return nil
+}
+
+func (c txConfig) accessMode() pgx.TxAccessMode {
+	if c.access == "" {
+		return pgx.ReadWrite
+	}
+	return c.access
+}
+
+// runNested wraps fn in a SAVEPOINT on the ambient transaction.
+func (m *TxManager) runNested(ctx context.Context, outer ambientTx, fn func(ctx context.Context, q *repo.Queries) error) (err error) {
+	sp, err := outer.tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("creating savepoint: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = sp.Rollback(ctx)
+			panic(p)
+		}
+		if err != nil {
+			_ = sp.Rollback(ctx)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, ambientTx{tx: sp, cfg: outer.cfg}), m.queries.WithTx(sp)); err != nil {
+		return err
+	}
+
+	return sp.Commit(ctx) // RELEASE SAVEPOINT
+}
+
+// QueriesFromContext returns q bound to the transaction carried by ctx, or q
+// itself when there is none. Repositories call it on every query so they
+// transparently join an ambient unit of work.
+func QueriesFromContext(ctx context.Context, q *repo.Queries) *repo.Queries {
+	if ambient, ok := ctx.Value(txKey{}).(ambientTx); ok {
+		return q.WithTx(ambient.tx)
+	}
+	return q
+}
+
+// isRetryable reports whether err is a serialization failure (40001) or a
+// deadlock (40P01), after which re-running the whole transaction may succeed.
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package postgresql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestCheckNested(t *testing.T) {
+	var (
+		defaults   = txConfig{}
+		snapshot   = txConfig{isolation: pgx.RepeatableRead, access: pgx.ReadOnly}
+		readCommit = txConfig{isolation: pgx.ReadCommitted}
+		serial     = txConfig{isolation: pgx.Serializable}
+	)
+
+	tests := []struct {
+		name     string
+		outer    txConfig
+		opts     []TxOption
+		conflict bool
+	}{
+		{"no options", snapshot, nil, false},
+		{"retry options are ignored", defaults, []TxOption{WithMaxRetries(0), WithBackoff(0)}, false},
+		{"same isolation", serial, []TxOption{WithIsolation(pgx.Serializable)}, false},
+		{"same access mode", snapshot, []TxOption{WithReadOnly()}, false},
+		{"read write inside the default", defaults, []TxOption{WithReadWrite()}, false},
+		{"Snapshot's options inside Snapshot", snapshot, []TxOption{WithIsolation(pgx.RepeatableRead), WithReadOnly()}, false},
+
+		{"read write inside read only", snapshot, []TxOption{WithReadWrite()}, true},
+		{"read only inside read write", defaults, []TxOption{WithReadOnly()}, true},
+		{"stronger isolation inside weaker", readCommit, []TxOption{WithIsolation(pgx.Serializable)}, true},
+		{"weaker isolation inside stronger", serial, []TxOption{WithIsolation(pgx.ReadCommitted)}, true},
+		{"isolation inside the server default", defaults, []TxOption{WithIsolation(pgx.RepeatableRead)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkNested(tt.outer, tt.opts)
+			if tt.conflict && !errors.Is(err, ErrTxOptionsConflict) {
+				t.Errorf("checkNested error = %v, want ErrTxOptionsConflict", err)
+			}
+			if !tt.conflict && err != nil {
+				t.Errorf("checkNested error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&pgconn.PgError{Code: "40001"}, true}, // serialization_failure
+		{&pgconn.PgError{Code: "40P01"}, true}, // deadlock_detected
+		{fmt.Errorf("creating entry: %w", &pgconn.PgError{Code: "40001"}), true},
+		{errors.Join(errors.New("rollback"), &pgconn.PgError{Code: "40P01"}), true},
+		{&pgconn.PgError{Code: "23505"}, false}, // unique_violation
+		{&pgconn.PgError{Code: "40002"}, false},
+		{errors.New("40001"), false},
+		{pgx.ErrNoRows, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := isRetryable(tt.err); got != tt.want {
+			t.Errorf("isRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package admin
+
+import "errors"
+
+// Sentinel errors for the admin domain.
+var (
+	// ErrUserNotFound is returned when the target user does not exist.
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrSelfAction is returned when an admin tries to lock or force a reset on
+	// their own account, which could leave nobody able to undo it.
+	ErrSelfAction = errors.New("admins cannot perform this action on their own account")
+
+	// ErrAccountPurging is returned when restoring an account whose purge has
+	// already started.
+	ErrAccountPurging = errors.New("account is being purged and can no longer be restored")
+)
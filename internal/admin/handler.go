@@ -0,0 +1,105 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/auth"
+	jsonutil "github.com/Ajay01103/goTransactonsAPI/internal/json"
+)
+
+// Handler holds all HTTP handlers for the admin domain. Every route is
+// mounted behind auth.RequireRole(auth.RoleAdmin).
+type Handler struct {
+	service Service
+}
+
+// NewHandler constructs a Handler with the given admin Service.
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListUsers handles GET /admin/users.
+func (h *Handler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	resp, err := h.service.ListUsers(r.Context(), ListUsersInput{
+		Search: r.URL.Query().Get("search"),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, resp)
+}
+
+// LockUser handles POST /admin/users/{id}/lock.
+func (h *Handler) LockUser(w http.ResponseWriter, r *http.Request) {
+	h.action(w, r, h.service.LockUser)
+}
+
+// UnlockUser handles POST /admin/users/{id}/unlock.
+func (h *Handler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	h.action(w, r, h.service.UnlockUser)
+}
+
+// ForcePasswordReset handles POST /admin/users/{id}/password-reset.
+func (h *Handler) ForcePasswordReset(w http.ResponseWriter, r *http.Request) {
+	h.action(w, r, h.service.ForcePasswordReset)
+}
+
+// RestoreUser handles POST /admin/users/{id}/restore.
+func (h *Handler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	h.action(w, r, h.service.RestoreUser)
+}
+
+// ListAuditLog handles GET /admin/audit-log.
+func (h *Handler) ListAuditLog(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	resp, err := h.service.ListAuditLog(r.Context(), ListInput{Limit: limit, Offset: offset})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, resp)
+}
+
+// action runs fn on behalf of the authenticated admin against the {id} user.
+func (h *Handler) action(w http.ResponseWriter, r *http.Request, fn func(ctx context.Context, adminID, userID string) error) {
+	adminID, ok := r.Context().Value(auth.ContextKeyUserID).(string)
+	if !ok || adminID == "" {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	if err := fn(r.Context(), adminID, chi.URLParam(r, "id")); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrUserNotFound):
+		jsonutil.Write(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrSelfAction):
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrAccountPurging):
+		jsonutil.Write(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql"
+	repo "github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql/sqlc"
+)
+
+type postgresRepository struct {
+	queries *repo.Queries
+}
+
+// NewPostgresRepository constructs an admin Repository backed by sqlc-generated Queries.
+// All sqlc and pgtype details are contained within this file — nothing leaks outward.
+func NewPostgresRepository(queries *repo.Queries) Repository {
+	return &postgresRepository{queries: queries}
+}
+
+func (r *postgresRepository) ListUsers(ctx context.Context, search string, limit, offset int) ([]User, error) {
+	rows, err := postgresql.QueriesFromContext(ctx, r.queries).ListUsers(ctx, repo.ListUsersParams{
+		Search:     search,
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]User, len(rows))
+	for i, row := range rows {
+		users[i] = User{
+			ID:                    row.ID,
+			Name:                  row.Name,
+			Email:                 row.Email,
+			Role:                  row.Role,
+			EmailVerified:         row.EmailVerifiedAt.Valid,
+			LockedAt:              row.LockedAt.Time,
+			LockedUntil:           row.LoginLockedUntil.Time,
+			PasswordResetRequired: row.PasswordResetRequired,
+			DeletedAt:             row.DeletedAt.Time,
+			CreatedAt:             row.CreatedAt.Time,
+		}
+	}
+	return users, nil
+}
+
+func (r *postgresRepository) CountUsers(ctx context.Context, search string) (int64, error) {
+	return postgresql.QueriesFromContext(ctx, r.queries).CountUsers(ctx, search)
+}
+
+func (r *postgresRepository) CreateAuditEntry(ctx context.Context, params CreateAuditEntryParams) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).CreateAdminAuditEntry(ctx, repo.CreateAdminAuditEntryParams{
+		ID:           params.ID,
+		ActorID:      params.ActorID,
+		Action:       params.Action,
+		TargetUserID: params.TargetUserID,
+	})
+}
+
+func (r *postgresRepository) ListAuditEntries(ctx context.Context, limit, offset int) ([]AuditEntry, error) {
+	rows, err := postgresql.QueriesFromContext(ctx, r.queries).ListAdminAuditEntries(ctx, repo.ListAdminAuditEntriesParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]AuditEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = AuditEntry{
+			ID:           row.ID,
+			ActorID:      row.ActorID,
+			Action:       row.Action,
+			TargetUserID: row.TargetUserID,
+			CreatedAt:    row.CreatedAt.Time,
+		}
+	}
+	return entries, nil
+}
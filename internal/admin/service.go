@@ -0,0 +1,166 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lucsky/cuid"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/auth"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 100
+)
+
+type svc struct {
+	repo     Repository
+	accounts Accounts
+}
+
+// NewService wires an admin Repository and the Accounts that carry out lock
+// and reset actions into a Service.
+func NewService(repo Repository, accounts Accounts) Service {
+	return &svc{repo: repo, accounts: accounts}
+}
+
+// ListUsers returns a page of users whose name or email contains the search
+// term, newest first, with the total number of matches.
+func (s *svc) ListUsers(ctx context.Context, input ListUsersInput) (UserListResponse, error) {
+	limit, offset := page(input.Limit, input.Offset)
+	search := strings.TrimSpace(input.Search)
+
+	users, err := s.repo.ListUsers(ctx, search, limit, offset)
+	if err != nil {
+		return UserListResponse{}, fmt.Errorf("listing users: %w", err)
+	}
+	total, err := s.repo.CountUsers(ctx, search)
+	if err != nil {
+		return UserListResponse{}, fmt.Errorf("counting users: %w", err)
+	}
+
+	resp := UserListResponse{
+		Users:  make([]UserResponse, 0, len(users)),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	for _, u := range users {
+		resp.Users = append(resp.Users, toUserResponse(u))
+	}
+
+	return resp, nil
+}
+
+// LockUser locks the account and signs it out everywhere.
+func (s *svc) LockUser(ctx context.Context, adminID, userID string) error {
+	if adminID == userID {
+		return ErrSelfAction
+	}
+	return s.act(ctx, adminID, userID, ActionLockUser, s.accounts.LockAccount)
+}
+
+// UnlockUser lets a locked account sign in again.
+func (s *svc) UnlockUser(ctx context.Context, adminID, userID string) error {
+	return s.act(ctx, adminID, userID, ActionUnlockUser, s.accounts.UnlockAccount)
+}
+
+// ForcePasswordReset signs the user out, blocks login until they choose a new
+// password and emails them a reset link.
+func (s *svc) ForcePasswordReset(ctx context.Context, adminID, userID string) error {
+	if adminID == userID {
+		return ErrSelfAction
+	}
+	return s.act(ctx, adminID, userID, ActionForcePasswordReset, s.accounts.RequirePasswordReset)
+}
+
+// RestoreUser cancels a pending account deletion before the account is purged.
+func (s *svc) RestoreUser(ctx context.Context, adminID, userID string) error {
+	return s.act(ctx, adminID, userID, ActionRestoreUser, s.accounts.RestoreAccount)
+}
+
+// ListAuditLog returns a page of audited actions, newest first.
+func (s *svc) ListAuditLog(ctx context.Context, input ListInput) (AuditLogResponse, error) {
+	limit, offset := page(input.Limit, input.Offset)
+
+	entries, err := s.repo.ListAuditEntries(ctx, limit, offset)
+	if err != nil {
+		return AuditLogResponse{}, fmt.Errorf("listing audit log: %w", err)
+	}
+
+	resp := AuditLogResponse{
+		Entries: make([]AuditEntryResponse, 0, len(entries)),
+		Limit:   limit,
+		Offset:  offset,
+	}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, AuditEntryResponse{
+			ID:           e.ID,
+			ActorID:      e.ActorID,
+			Action:       e.Action,
+			TargetUserID: e.TargetUserID,
+			CreatedAt:    e.CreatedAt,
+		})
+	}
+
+	return resp, nil
+}
+
+// act runs an account change that records itself in the audit log in the
+// change's own transaction, so no action goes unrecorded and nothing outside
+// the database happens before both have committed.
+func (s *svc) act(ctx context.Context, adminID, userID, action string, fn func(context.Context, string, auth.AuditFunc) error) error {
+	err := fn(ctx, userID, func(ctx context.Context) error {
+		return s.repo.CreateAuditEntry(ctx, CreateAuditEntryParams{
+			ID:           cuid.New(),
+			ActorID:      adminID,
+			Action:       action,
+			TargetUserID: userID,
+		})
+	})
+	if err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			return ErrUserNotFound
+		}
+		if errors.Is(err, auth.ErrAccountPurging) {
+			return ErrAccountPurging
+		}
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	return nil
+}
+
+func page(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return limit, max(offset, 0)
+}
+
+func toUserResponse(u User) UserResponse {
+	resp := UserResponse{
+		ID:                    u.ID,
+		Name:                  u.Name,
+		Email:                 u.Email,
+		Role:                  u.Role,
+		EmailVerified:         u.EmailVerified,
+		PasswordResetRequired: u.PasswordResetRequired,
+		CreatedAt:             u.CreatedAt,
+	}
+	if !u.LockedAt.IsZero() {
+		resp.LockedAt = &u.LockedAt
+	}
+	if !u.LockedUntil.IsZero() {
+		resp.LockedUntil = &u.LockedUntil
+	}
+	if !u.DeletedAt.IsZero() {
+		resp.DeletedAt = &u.DeletedAt
+	}
+	return resp
+}
@@ -0,0 +1,138 @@
+package admin
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/auth"
+)
+
+// ── Domain model ──────────────────────────────────────────────────────────────
+
+// Action names recorded in the audit log for admin actions. Account actions
+// a user takes on their own (account.*) are recorded by the auth and privacy
+// packages.
+const (
+	ActionLockUser           = "user.lock"
+	ActionUnlockUser         = "user.unlock"
+	ActionForcePasswordReset = "user.force_password_reset"
+	ActionRestoreUser        = "user.restore"
+)
+
+// User is an account as seen by administrators — no password hash.
+type User struct {
+	ID                    string
+	Name                  string
+	Email                 string
+	Role                  string
+	EmailVerified         bool
+	LockedAt              time.Time // zero unless locked
+	LockedUntil           time.Time // brute-force lockout of the email; zero when none is in force
+	PasswordResetRequired bool
+	DeletedAt             time.Time // set while a deleted account waits to be purged
+	CreatedAt             time.Time
+}
+
+// AuditEntry records one admin action, or an account action a user or the
+// API took on its own.
+type AuditEntry struct {
+	ID           string
+	ActorID      string // the admin who acted; the user, or "system", for account actions
+	Action       string
+	TargetUserID string
+	CreatedAt    time.Time
+}
+
+// ── Service DTOs ──────────────────────────────────────────────────────────────
+
+// ListUsersInput carries the search term and pagination for listing users.
+type ListUsersInput struct {
+	Search string // matched against name and email; empty lists everyone
+	Limit  int
+	Offset int
+}
+
+// ListInput carries pagination for the audit log.
+type ListInput struct {
+	Limit  int
+	Offset int
+}
+
+// UserResponse is the public DTO for a user in the admin API.
+type UserResponse struct {
+	ID                    string     `json:"id"`
+	Name                  string     `json:"name"`
+	Email                 string     `json:"email"`
+	Role                  string     `json:"role"`
+	EmailVerified         bool       `json:"email_verified"`
+	LockedAt              *time.Time `json:"locked_at,omitempty"`
+	LockedUntil           *time.Time `json:"locked_until,omitempty"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+	DeletedAt             *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+// UserListResponse wraps a page of users.
+type UserListResponse struct {
+	Users  []UserResponse `json:"users"`
+	Total  int64          `json:"total"` // matching users across all pages
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+// AuditEntryResponse is the public DTO for an audit log entry.
+type AuditEntryResponse struct {
+	ID           string    `json:"id"`
+	ActorID      string    `json:"actor_id"`
+	Action       string    `json:"action"`
+	TargetUserID string    `json:"target_user_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AuditLogResponse wraps a page of audit log entries.
+type AuditLogResponse struct {
+	Entries []AuditEntryResponse `json:"entries"`
+	Limit   int                  `json:"limit"`
+	Offset  int                  `json:"offset"`
+}
+
+// ── Repository DTOs ───────────────────────────────────────────────────────────
+
+// CreateAuditEntryParams carries a new audit log entry.
+type CreateAuditEntryParams struct {
+	ID           string
+	ActorID      string
+	Action       string
+	TargetUserID string
+}
+
+// ── Contracts ─────────────────────────────────────────────────────────────────
+
+// Repository defines the data-access contract for the admin domain.
+// All method signatures use domain types only — no sqlc or pgtype.
+type Repository interface {
+	ListUsers(ctx context.Context, search string, limit, offset int) ([]User, error)
+	CountUsers(ctx context.Context, search string) (int64, error)
+	CreateAuditEntry(ctx context.Context, params CreateAuditEntryParams) error
+	ListAuditEntries(ctx context.Context, limit, offset int) ([]AuditEntry, error)
+}
+
+// Accounts performs the account changes behind admin actions. auth.Service
+// implements it.
+type Accounts interface {
+	LockAccount(ctx context.Context, userID string, audit auth.AuditFunc) error
+	UnlockAccount(ctx context.Context, userID string, audit auth.AuditFunc) error
+	RequirePasswordReset(ctx context.Context, userID string, audit auth.AuditFunc) error
+	RestoreAccount(ctx context.Context, userID string, audit auth.AuditFunc) error
+}
+
+// Service defines the business-logic contract for the admin domain.
+// adminID is the acting administrator and is recorded with every action.
+type Service interface {
+	ListUsers(ctx context.Context, input ListUsersInput) (UserListResponse, error)
+	LockUser(ctx context.Context, adminID, userID string) error
+	UnlockUser(ctx context.Context, adminID, userID string) error
+	ForcePasswordReset(ctx context.Context, adminID, userID string) error
+	RestoreUser(ctx context.Context, adminID, userID string) error
+	ListAuditLog(ctx context.Context, input ListInput) (AuditLogResponse, error)
+}
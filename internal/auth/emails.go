@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/mailer"
+)
+
+// withToken appends ?token=raw (or &token=raw) to base.
+func withToken(base, raw string) string {
+	sep := "?"
+	if strings.Contains(base, "?") {
+		sep = "&"
+	}
+	return base + sep + "token=" + url.QueryEscape(raw)
+}
+
+func passwordResetEmail(user User, link string, ttl time.Duration) mailer.Message {
+	return mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(`Hi %s,
+
+Someone asked to reset the password for your account. If it was you, open the
+link below to choose a new one. It expires in %s and works once.
+
+%s
+
+If you did not ask for this, you can ignore this email; your password is unchanged.
+`, user.Name, ttl, link),
+	}
+}
+
+func magicLinkEmail(user User, link string, ttl time.Duration) mailer.Message {
+	return mailer.Message{
+		To:      user.Email,
+		Subject: "Your login link",
+		Body: fmt.Sprintf(`Hi %s,
+
+Open the link below to log in. It expires in %s, works once, and only in the
+browser where you asked for it.
+
+%s
+
+If you did not ask for this, you can ignore this email.
+`, user.Name, ttl, link),
+	}
+}
+
+func verificationEmail(user User, link string, ttl time.Duration) mailer.Message {
+	return mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf(`Hi %s,
+
+Please confirm this is your email address by opening the link below. It
+expires in %s.
+
+%s
+
+If you did not create an account, you can ignore this email.
+`, user.Name, ttl, link),
+	}
+}
+
+func passwordChangedEmail(user User) mailer.Message {
+	return mailer.Message{
+		To:      user.Email,
+		Subject: "Your password was changed",
+		Body: fmt.Sprintf(`Hi %s,
+
+The password for your account was just changed, and your other sessions were
+signed out.
+
+If you did not do this, reset your password right away using "Forgot password"
+on the login page.
+`, user.Name),
+	}
+}
+
+func emailChangeEmail(user User, newEmail, link string, ttl time.Duration) mailer.Message {
+	return mailer.Message{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf(`Hi %s,
+
+You asked to use this address for your account instead of %s. Open the link
+below to confirm. It expires in %s and works once.
+
+%s
+
+If you did not ask for this, you can ignore this email; nothing changes.
+`, user.Name, user.Email, ttl, link),
+	}
+}
+
+func emailChangedEmail(user User, newEmail string) mailer.Message {
+	return mailer.Message{
+		To:      user.Email,
+		Subject: "Your email address was changed",
+		Body: fmt.Sprintf(`Hi %s,
+
+The email address of your account was changed from this address to %s.
+Account emails will go there from now on.
+
+If you did not do this, contact support right away.
+`, user.Name, newEmail),
+	}
+}
+
+func accountDeletedEmail(user User, purgeAfter time.Time) mailer.Message {
+	return mailer.Message{
+		To:      user.Email,
+		Subject: "Your account was deleted",
+		Body: fmt.Sprintf(`Hi %s,
+
+Your account was deleted and signed out everywhere. It and all of its data,
+including your transactions and ledger, will be erased permanently after
+%s. Until then, support can still restore it.
+
+If you did not do this, contact support right away.
+`, user.Name, purgeAfter.UTC().Format("2 January 2006 15:04 MST")),
+	}
+}
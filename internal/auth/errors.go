@@ -1,9 +1,123 @@
 package auth
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Sentinel errors for the auth domain.
 var (
 	// ErrEmailTaken is returned when a registration attempt uses an email that already exists.
 	ErrEmailTaken = errors.New("email already in use")
+
+	// ErrInvalidEmail is returned when a registration email is not a bare RFC 5322 address.
+	ErrInvalidEmail = errors.New("invalid email address")
+
+	// ErrInvalidCredentials is returned by Login for an unknown email or a wrong password.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+
+	// ErrInvalidRefreshToken is returned when a refresh token is unknown, expired or revoked.
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+	// ErrRefreshTokenReused is returned when an already-rotated refresh token is presented
+	// again. The whole token family has been revoked by the time this is returned.
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+
+	// ErrTokenRevoked is returned when an access token was logged out, predates a
+	// logout-all, or belongs to a user that no longer exists.
+	ErrTokenRevoked = errors.New("token has been revoked")
+
+	// ErrSessionNotFound is returned when revoking a session the caller does not
+	// own or that has already ended.
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrInvalidResetToken is returned when a password reset token is unknown, used or expired.
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+
+	// ErrInvalidEmailChangeToken is returned when an email change confirmation is
+	// unknown, used or expired.
+	ErrInvalidEmailChangeToken = errors.New("invalid or expired email change link")
+
+	// ErrEmailUnchanged is returned when an email change names the current address.
+	ErrEmailUnchanged = errors.New("new email is the current email")
+
+	// ErrEmailChangeThrottled is returned when email changes are requested too often.
+	ErrEmailChangeThrottled = errors.New("too many email changes requested, try again later")
+
+	// ErrInvalidMagicLink is returned when a login link is unknown, used or expired.
+	ErrInvalidMagicLink = errors.New("invalid or expired login link")
+
+	// ErrMagicLinkWrongBrowser is returned when a login link is opened in a
+	// browser other than the one that requested it. The link stays valid.
+	ErrMagicLinkWrongBrowser = errors.New("open the login link in the browser that requested it")
+
+	// ErrInvalidVerificationToken is returned when an email verification token is unknown, used or expired.
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+
+	// ErrEmailAlreadyVerified is returned when a verification email is requested for a verified account.
+	ErrEmailAlreadyVerified = errors.New("email already verified")
+
+	// ErrVerificationThrottled is returned when verification emails are requested too often.
+	ErrVerificationThrottled = errors.New("too many verification emails requested, try again later")
+
+	// ErrTOTPNotEnrolled is returned when confirming TOTP before calling setup.
+	ErrTOTPNotEnrolled = errors.New("two-factor setup has not been started")
+
+	// ErrTOTPAlreadyEnabled is returned when setting up TOTP on an account that already has it.
+	ErrTOTPAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+
+	// ErrInvalidTOTPCode is returned when a TOTP or recovery code does not match,
+	// or a TOTP code was already used.
+	ErrInvalidTOTPCode = errors.New("invalid authentication code")
+
+	// ErrInvalidMFAChallenge is returned when an mfa_token is unknown, used,
+	// expired or out of attempts. The user must log in again.
+	ErrInvalidMFAChallenge = errors.New("invalid or expired mfa token")
+
+	// ErrInvalidAPIKey is returned when an API key is unknown or expired.
+	ErrInvalidAPIKey = errors.New("invalid or expired api key")
+
+	// ErrAPIKeyNotFound is returned when deleting an API key the caller does not own.
+	ErrAPIKeyNotFound = errors.New("api key not found")
+
+	// ErrInvalidScope is returned when an API key is requested with an unknown scope.
+	ErrInvalidScope = errors.New("invalid api key scope")
+
+	// ErrUserNotFound is returned by admin account actions for unknown users.
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrAccountLocked is returned when a locked account tries to sign in.
+	ErrAccountLocked = errors.New("account is locked")
+
+	// ErrAccountDeleted is returned when a deleted account tries to sign in
+	// during its grace period. It wraps ErrAccountLocked, so callers that
+	// refuse locked accounts refuse deleted ones too.
+	ErrAccountDeleted = fmt.Errorf("%w: it is scheduled for deletion", ErrAccountLocked)
+
+	// ErrAccountPurging is returned when restoring a deleted account whose
+	// purge has already started.
+	ErrAccountPurging = errors.New("account is being purged and can no longer be restored")
+
+	// ErrPasswordResetRequired is returned on login after an admin demanded a
+	// password reset; the user must follow the emailed link first.
+	ErrPasswordResetRequired = errors.New("password reset required, check your email")
+
+	// ErrTooManyLoginAttempts is wrapped by LoginLockedError while an email or
+	// client IP is locked out after repeated failed logins.
+	ErrTooManyLoginAttempts = errors.New("too many failed login attempts")
+
+	// ErrUnknownOIDCProvider is returned for a provider name that is not configured.
+	ErrUnknownOIDCProvider = errors.New("unknown identity provider")
+
+	// ErrInvalidOIDCState is returned when an OIDC callback's state is unknown,
+	// expired, already used or not bound to the calling browser.
+	ErrInvalidOIDCState = errors.New("invalid or expired login state")
+
+	// ErrOIDCLoginFailed is returned when the provider rejects the authorization
+	// code or returns an ID token that does not verify.
+	ErrOIDCLoginFailed = errors.New("identity provider login failed")
+
+	// ErrOIDCEmailNotVerified is returned when a new identity cannot be linked or
+	// signed up because the provider did not vouch for its email address.
+	ErrOIDCEmailNotVerified = errors.New("identity provider did not return a verified email")
 )
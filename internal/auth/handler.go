@@ -1,8 +1,16 @@
 package auth
 
 import (
+	"crypto/subtle"
 	"errors"
+	"log/slog"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
 
 	jsonutil "github.com/Ajay01103/goTransactonsAPI/internal/json"
 )
@@ -17,6 +25,19 @@ func NewHandler(service Service) *Handler {
 	return &Handler{service: service}
 }
 
+// magicLinkCookieName holds the nonce that binds a login link to the browser
+// that requested it.
+const magicLinkCookieName = "magic_link_nonce"
+
+// JWKSHandler handles GET /.well-known/jwks.json, publishing the public keys that
+// verify our access tokens so other services need no shared secret.
+func JWKSHandler(keys *KeySet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		jsonutil.Write(w, http.StatusOK, keys.JWKS())
+	}
+}
+
 type registerRequest struct {
 	Name           string `json:"name"`
 	Email          string `json:"email"`
@@ -42,16 +63,34 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.service.Login(r.Context(), LoginInput{
+	result, err := h.service.Login(r.Context(), LoginInput{
 		Email:    req.Email,
 		Password: req.Password,
+		Client:   clientInfo(r),
 	})
 	if err != nil {
-		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "invalid credentials"})
+		var locked *LoginLockedError
+		switch {
+		case errors.As(err, &locked):
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+			jsonutil.Write(w, http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrInvalidCredentials):
+			jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrAccountLocked), errors.Is(err, ErrPasswordResetRequired):
+			jsonutil.Write(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+		default:
+			jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to log in"})
+		}
 		return
 	}
 
-	jsonutil.Write(w, http.StatusOK, resp)
+	// with 2FA enabled the client must call /auth/2fa/verify next
+	if result.MFA != nil {
+		jsonutil.Write(w, http.StatusOK, result.MFA)
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, result.Auth)
 }
 
 // Register handles POST /auth/register.
@@ -75,15 +114,787 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		Email:          req.Email,
 		Password:       req.Password,
 		ProfilePicture: profilePicture,
+		Client:         clientInfo(r),
 	})
 	if err != nil {
 		if errors.Is(err, ErrEmailTaken) {
 			jsonutil.Write(w, http.StatusConflict, map[string]string{"error": "an account with this email already exists"})
 			return
 		}
+		if errors.Is(err, ErrInvalidEmail) {
+			jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
 		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to create user"})
 		return
 	}
 
 	jsonutil.Write(w, http.StatusCreated, resp)
 }
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Refresh handles POST /auth/refresh.
+func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req refreshRequest
+	if err := jsonutil.Read(r, &req); err != nil {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if req.RefreshToken == "" {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "refresh_token is required"})
+		return
+	}
+
+	resp, err := h.service.Refresh(r.Context(), RefreshInput{RefreshToken: req.RefreshToken, Client: clientInfo(r)})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidRefreshToken), errors.Is(err, ErrRefreshTokenReused):
+			jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrAccountLocked):
+			jsonutil.Write(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+		default:
+			jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to refresh token"})
+		}
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, resp)
+}
+
+// Logout handles POST /auth/logout. It ends the session of the bearer token.
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	input := LogoutInput{
+		UserID:    claims.UserID,
+		TokenID:   claims.ID,
+		SessionID: claims.SessionID,
+	}
+	if claims.ExpiresAt != nil {
+		input.ExpiresAt = claims.ExpiresAt.Time
+	}
+
+	if err := h.service.Logout(r.Context(), input); err != nil {
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to log out"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LogoutAll handles POST /auth/logout-all. It ends every session of the caller.
+func (h *Handler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(ContextKeyUserID).(string)
+	if !ok || userID == "" {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.service.LogoutAll(r.Context(), userID); err != nil {
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to log out"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type forgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type resetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// ForgotPassword handles POST /auth/password/forgot. It answers 202 whether
+// or not the email belongs to an account.
+func (h *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req forgotPasswordRequest
+	if err := jsonutil.Read(r, &req); err != nil {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if req.Email == "" {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "email is required"})
+		return
+	}
+
+	if err := h.service.ForgotPassword(r.Context(), ForgotPasswordInput{Email: req.Email}); err != nil {
+		// still 202: a different status would reveal that the account exists
+		slog.ErrorContext(r.Context(), "password reset request failed", "error", err)
+	}
+
+	jsonutil.Write(w, http.StatusAccepted, map[string]string{"message": "if an account exists for this email, a reset link has been sent"})
+}
+
+type magicLinkRequest struct {
+	Email string `json:"email"`
+}
+
+// RequestMagicLink handles POST /auth/magic-link. It gives the browser a nonce
+// cookie the link is bound to and answers 202 whether or not the email
+// belongs to an account.
+func (h *Handler) RequestMagicLink(w http.ResponseWriter, r *http.Request) {
+	var req magicLinkRequest
+	if err := jsonutil.Read(r, &req); err != nil {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if req.Email == "" {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "email is required"})
+		return
+	}
+
+	nonce, _, err := newOpaqueToken()
+	if err != nil {
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to send login link"})
+		return
+	}
+
+	if err := h.service.RequestMagicLink(r.Context(), MagicLinkInput{Email: req.Email, BrowserNonce: nonce}); err != nil {
+		// still 202: a different status would reveal that the account exists
+		slog.ErrorContext(r.Context(), "magic link request failed", "error", err)
+	}
+
+	http.SetCookie(w, magicLinkCookie(r, nonce, 0))
+	jsonutil.Write(w, http.StatusAccepted, map[string]string{"message": "if an account exists for this email, a login link has been sent"})
+}
+
+// ConsumeMagicLink handles GET /auth/magic-link/consume?token=, the target of
+// the emailed login link. It responds like Login.
+func (h *Handler) ConsumeMagicLink(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "token is required"})
+		return
+	}
+
+	var nonce string
+	if cookie, err := r.Cookie(magicLinkCookieName); err == nil {
+		nonce = cookie.Value
+	}
+
+	result, err := h.service.ConsumeMagicLink(r.Context(), ConsumeMagicLinkInput{
+		Token:        token,
+		BrowserNonce: nonce,
+		Client:       clientInfo(r),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidMagicLink):
+			jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrMagicLinkWrongBrowser), errors.Is(err, ErrAccountLocked), errors.Is(err, ErrPasswordResetRequired):
+			jsonutil.Write(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+		default:
+			jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to log in"})
+		}
+		return
+	}
+
+	http.SetCookie(w, magicLinkCookie(r, "", -1))
+
+	if result.MFA != nil {
+		jsonutil.Write(w, http.StatusOK, result.MFA)
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, result.Auth)
+}
+
+// magicLinkCookie carries the browser nonce a login link is bound to. It is a
+// session cookie; the link's own expiry bounds how long it matters.
+func magicLinkCookie(r *http.Request, value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     magicLinkCookieName,
+		Value:    value,
+		Path:     "/auth/magic-link",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// ResetPassword handles POST /auth/password/reset.
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req resetPasswordRequest
+	if err := jsonutil.Read(r, &req); err != nil {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if req.Token == "" || req.NewPassword == "" {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "token and new_password are required"})
+		return
+	}
+
+	err := h.service.ResetPassword(r.Context(), ResetPasswordInput{
+		Token:       req.Token,
+		NewPassword: req.NewPassword,
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidResetToken) {
+			jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to reset password"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// VerifyEmail handles GET /auth/verify-email?token=. It is the target of the
+// emailed verification link.
+func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "token is required"})
+		return
+	}
+
+	if err := h.service.VerifyEmail(r.Context(), VerifyEmailInput{Token: token}); err != nil {
+		if errors.Is(err, ErrInvalidVerificationToken) {
+			jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to verify email"})
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, map[string]string{"message": "email verified"})
+}
+
+// ResendVerification handles POST /auth/verify-email/resend.
+func (h *Handler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(ContextKeyUserID).(string)
+	if !ok || userID == "" {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.service.ResendVerification(r.Context(), userID); err != nil {
+		switch {
+		case errors.Is(err, ErrEmailAlreadyVerified):
+			jsonutil.Write(w, http.StatusConflict, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrVerificationThrottled):
+			jsonutil.Write(w, http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+		default:
+			jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to send verification email"})
+		}
+		return
+	}
+
+	jsonutil.Write(w, http.StatusAccepted, map[string]string{"message": "verification email sent"})
+}
+
+type confirmTOTPRequest struct {
+	Code string `json:"code"`
+}
+
+type verifyMFARequest struct {
+	MFAToken     string `json:"mfa_token"`
+	Code         string `json:"code,omitempty"`
+	RecoveryCode string `json:"recovery_code,omitempty"`
+}
+
+// SetupTOTP handles POST /auth/2fa/totp/setup.
+func (h *Handler) SetupTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(ContextKeyUserID).(string)
+	if !ok || userID == "" {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.SetupTOTP(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, ErrTOTPAlreadyEnabled) {
+			jsonutil.Write(w, http.StatusConflict, map[string]string{"error": err.Error()})
+			return
+		}
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to start two-factor setup"})
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, resp)
+}
+
+// ConfirmTOTP handles POST /auth/2fa/totp/confirm.
+func (h *Handler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(ContextKeyUserID).(string)
+	if !ok || userID == "" {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	var req confirmTOTPRequest
+	if err := jsonutil.Read(r, &req); err != nil {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if req.Code == "" {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "code is required"})
+		return
+	}
+
+	resp, err := h.service.ConfirmTOTP(r.Context(), ConfirmTOTPInput{UserID: userID, Code: req.Code})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidTOTPCode), errors.Is(err, ErrTOTPNotEnrolled):
+			jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrTOTPAlreadyEnabled):
+			jsonutil.Write(w, http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to confirm two-factor setup"})
+		}
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, resp)
+}
+
+// VerifyMFA handles POST /auth/2fa/verify, the second step of Login.
+func (h *Handler) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	var req verifyMFARequest
+	if err := jsonutil.Read(r, &req); err != nil {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if req.MFAToken == "" || (req.Code == "") == (req.RecoveryCode == "") {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "mfa_token and exactly one of code or recovery_code are required"})
+		return
+	}
+
+	resp, err := h.service.VerifyMFA(r.Context(), VerifyMFAInput{
+		MFAToken:     req.MFAToken,
+		Code:         req.Code,
+		RecoveryCode: req.RecoveryCode,
+		Client:       clientInfo(r),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidTOTPCode), errors.Is(err, ErrInvalidMFAChallenge):
+			jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrAccountLocked):
+			jsonutil.Write(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+		default:
+			jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to verify two-factor code"})
+		}
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, resp)
+}
+
+// OIDCStart handles GET /auth/oidc/{provider}/start by redirecting the
+// browser to the identity provider's login page.
+func (h *Handler) OIDCStart(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+
+	req, err := h.service.StartOIDC(r.Context(), provider)
+	if err != nil {
+		if errors.Is(err, ErrUnknownOIDCProvider) {
+			jsonutil.Write(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		slog.ErrorContext(r.Context(), "starting oidc login", "provider", provider, "error", err)
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to start login"})
+		return
+	}
+
+	http.SetCookie(w, oidcCookie(r, provider, req.State, int(req.ExpiresIn/time.Second)))
+	http.Redirect(w, r, req.URL, http.StatusFound)
+}
+
+// OIDCCallback handles GET /auth/oidc/{provider}/callback, where the identity
+// provider sends the browser back with an authorization code. It responds like
+// Login: tokens, or an MFA challenge.
+func (h *Handler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+	query := r.URL.Query()
+
+	// the state is single-use whatever happens next
+	http.SetCookie(w, oidcCookie(r, provider, "", -1))
+
+	if reason := query.Get("error"); reason != "" {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "identity provider returned " + reason})
+		return
+	}
+
+	state, code := query.Get("state"), query.Get("code")
+	if state == "" || code == "" {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "state and code are required"})
+		return
+	}
+
+	cookie, err := r.Cookie(oidcStateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": ErrInvalidOIDCState.Error()})
+		return
+	}
+
+	result, err := h.service.CompleteOIDC(r.Context(), OIDCCallbackInput{
+		Provider: provider,
+		State:    state,
+		Code:     code,
+		Client:   clientInfo(r),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnknownOIDCProvider):
+			jsonutil.Write(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrInvalidOIDCState):
+			jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrOIDCLoginFailed):
+			jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrOIDCEmailNotVerified), errors.Is(err, ErrAccountLocked):
+			jsonutil.Write(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrEmailTaken):
+			jsonutil.Write(w, http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to log in"})
+		}
+		return
+	}
+
+	if result.MFA != nil {
+		jsonutil.Write(w, http.StatusOK, result.MFA)
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, result.Auth)
+}
+
+// oidcCookie scopes the state cookie to one provider's OIDC routes. Lax keeps
+// it on the top-level redirect back from the provider.
+func oidcCookie(r *http.Request, provider, value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    value,
+		Path:     "/auth/oidc/" + provider,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// isHTTPS reports whether the client reached us over TLS, directly or through
+// a proxy that sets X-Forwarded-Proto, so cookies can be marked Secure.
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
+
+type createAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// CreateAPIKey handles POST /users/me/api-keys.
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(ContextKeyUserID).(string)
+	if !ok || userID == "" {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	var req createAPIKeyRequest
+	if err := jsonutil.Read(r, &req); err != nil {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if req.Name == "" || len(req.Scopes) == 0 {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "name and scopes are required"})
+		return
+	}
+
+	input := CreateAPIKeyInput{UserID: userID, Name: req.Name, Scopes: req.Scopes}
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "expires_at must be in the future"})
+			return
+		}
+		input.ExpiresAt = *req.ExpiresAt
+	}
+
+	resp, err := h.service.CreateAPIKey(r.Context(), input)
+	if err != nil {
+		if errors.Is(err, ErrInvalidScope) {
+			jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to create api key"})
+		return
+	}
+
+	jsonutil.Write(w, http.StatusCreated, resp)
+}
+
+// ListAPIKeys handles GET /users/me/api-keys.
+func (h *Handler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(ContextKeyUserID).(string)
+	if !ok || userID == "" {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.ListAPIKeys(r.Context(), userID)
+	if err != nil {
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to list api keys"})
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, resp)
+}
+
+// DeleteAPIKey handles DELETE /users/me/api-keys/{id}.
+func (h *Handler) DeleteAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(ContextKeyUserID).(string)
+	if !ok || userID == "" {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.service.DeleteAPIKey(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, ErrAPIKeyNotFound) {
+			jsonutil.Write(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete api key"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// ChangePassword handles POST /users/me/password. Other sessions are signed
+// out; the caller's stays valid.
+func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	var req changePasswordRequest
+	if err := jsonutil.Read(r, &req); err != nil {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "current_password and new_password are required"})
+		return
+	}
+
+	err := h.service.ChangePassword(r.Context(), ChangePasswordInput{
+		UserID:          claims.UserID,
+		SessionID:       claims.SessionID,
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+		Client:          clientInfo(r),
+	})
+	if err != nil {
+		writeReauthError(w, err, "failed to change password")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type changeEmailRequest struct {
+	NewEmail string `json:"new_email"`
+	Password string `json:"password"`
+}
+
+// RequestEmailChange handles POST /users/me/email. The address changes only
+// once the link emailed to the new address is opened.
+func (h *Handler) RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(ContextKeyUserID).(string)
+	if !ok || userID == "" {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	var req changeEmailRequest
+	if err := jsonutil.Read(r, &req); err != nil {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if req.NewEmail == "" || req.Password == "" {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "new_email and password are required"})
+		return
+	}
+
+	err := h.service.RequestEmailChange(r.Context(), ChangeEmailInput{
+		UserID:   userID,
+		NewEmail: req.NewEmail,
+		Password: req.Password,
+		Client:   clientInfo(r),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidEmail), errors.Is(err, ErrEmailUnchanged):
+			jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrEmailTaken):
+			jsonutil.Write(w, http.StatusConflict, map[string]string{"error": "an account with this email already exists"})
+		case errors.Is(err, ErrEmailChangeThrottled):
+			jsonutil.Write(w, http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+		default:
+			writeReauthError(w, err, "failed to request email change")
+		}
+		return
+	}
+
+	jsonutil.Write(w, http.StatusAccepted, map[string]string{"message": "check your new inbox to confirm the change"})
+}
+
+type deleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// DeleteAccount handles DELETE /users/me. The account is signed out at once
+// and purged after the grace period given in the response.
+func (h *Handler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(ContextKeyUserID).(string)
+	if !ok || userID == "" {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	var req deleteAccountRequest
+	if err := jsonutil.Read(r, &req); err != nil {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if req.Password == "" {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "password is required"})
+		return
+	}
+
+	resp, err := h.service.DeleteAccount(r.Context(), DeleteAccountInput{
+		UserID:   userID,
+		Password: req.Password,
+		Client:   clientInfo(r),
+	})
+	if err != nil {
+		writeReauthError(w, err, "failed to delete account")
+		return
+	}
+
+	jsonutil.Write(w, http.StatusAccepted, resp)
+}
+
+// ConfirmEmailChange handles GET /auth/email-change/confirm?token=. It is the
+// target of the link emailed to the new address.
+func (h *Handler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "token is required"})
+		return
+	}
+
+	if err := h.service.ConfirmEmailChange(r.Context(), ConfirmEmailChangeInput{Token: token}); err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidEmailChangeToken):
+			jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrEmailTaken):
+			jsonutil.Write(w, http.StatusConflict, map[string]string{"error": "an account with this email already exists"})
+		default:
+			jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to change email"})
+		}
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, map[string]string{"message": "email changed"})
+}
+
+// writeReauthError maps the errors of a password re-check. A wrong password
+// is 403 rather than 401 so clients do not mistake it for an expired token.
+func writeReauthError(w http.ResponseWriter, err error, fallback string) {
+	var locked *LoginLockedError
+	switch {
+	case errors.As(err, &locked):
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		jsonutil.Write(w, http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrInvalidCredentials):
+		jsonutil.Write(w, http.StatusForbidden, map[string]string{"error": "current password is incorrect"})
+	default:
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": fallback})
+	}
+}
+
+// ListSessions handles GET /users/me/sessions.
+func (h *Handler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.ListSessions(r.Context(), claims.UserID, claims.SessionID)
+	if err != nil {
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to list sessions"})
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, resp)
+}
+
+// DeleteSession handles DELETE /users/me/sessions/{id}. Deleting the current
+// session is allowed and works like a logout.
+func (h *Handler) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(ContextKeyUserID).(string)
+	if !ok || userID == "" {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.service.RevokeSession(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			jsonutil.Write(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to revoke session"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// clientInfo describes the client making r, for the session it may start.
+func clientInfo(r *http.Request) ClientInfo {
+	return ClientInfo{UserAgent: r.UserAgent(), IP: clientIP(r)}
+}
+
+// clientIP returns the caller's address without the port. clientip.Middleware
+// has already replaced RemoteAddr with the forwarded address when the request
+// came through a trusted proxy.
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -5,31 +5,45 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/lucsky/cuid"
 )
 
-const tokenTTL = 7 * 24 * time.Hour // 7 days
-
+// Claims is the payload of an access token. RegisteredClaims.ID carries the
+// jti used for per-token revocation; TokenVersion must match the user's
+// current version or the token is treated as logged out. EmailVerified is a
+// snapshot from issuance; RequireVerifiedEmail re-checks when it is false.
+// Role is likewise a snapshot: a role change applies from the next token.
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
+	UserID        string `json:"user_id"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Role          string `json:"role"`
+	SessionID     string `json:"sid"`
+	TokenVersion  int32  `json:"ver"`
 	jwt.RegisteredClaims
 }
 
-// generateToken creates a signed HS256 JWT for the given user with a 7-day TTL.
-func generateToken(userID, email, secret string) (string, error) {
+// generateToken creates a JWT for the given user that expires after ttl, signed
+// by the KeySet's current signing key. sessionID is the refresh-token family
+// the access token belongs to.
+func generateToken(user User, sessionID string, keys *KeySet, ttl time.Duration) (string, error) {
 	now := time.Now()
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:        user.ID,
+		Email:         user.Email,
+		EmailVerified: !user.EmailVerifiedAt.IsZero(),
+		Role:          user.Role,
+		SessionID:     sessionID,
+		TokenVersion:  user.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
-			Subject:   userID,
+			ID:        cuid.New(),
+			Subject:   user.ID,
 			IssuedAt:  jwt.NewNumericDate(now),
-			ExpiresAt: jwt.NewNumericDate(now.Add(tokenTTL)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signed, err := token.SignedString([]byte(secret))
+	signed, err := keys.sign(claims)
 	if err != nil {
 		return "", fmt.Errorf("signing token: %w", err)
 	}
@@ -0,0 +1,254 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSABits is the smallest RSA modulus accepted for signing or verification.
+const minRSABits = 2048
+
+// KeySet holds the key that signs new access tokens and every key whose
+// signatures are still accepted. Keys are identified by a kid — the RFC 7638
+// thumbprint of the public key — so every instance derives the same kid from
+// the same PEM file without extra configuration.
+//
+// To rotate: add the new key's PEM as the signing key and keep the old one
+// as a verification key until the last token it signed has expired.
+type KeySet struct {
+	signing *jwtKey
+	verify  map[string]*jwtKey
+}
+
+type jwtKey struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   any  // *rsa.PrivateKey, ed25519.PrivateKey or []byte; nil for verify-only keys
+	verifyKey any  // *rsa.PublicKey, ed25519.PublicKey or []byte
+	jwk       *JWK // nil for HMAC keys, which must never be published
+}
+
+// JWK is the public half of a signing key in RFC 7517 form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // OKP curve
+	X   string `json:"x,omitempty"`   // OKP public key
+}
+
+// JWKS is the document served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewHMACKeySet returns a KeySet that signs and verifies with a shared HS256
+// secret. It exists for local development; HMAC keys are never published in
+// the JWKS, so other services cannot verify these tokens.
+func NewHMACKeySet(secret string) *KeySet {
+	key := &jwtKey{
+		id:        "hs256",
+		method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}
+	return &KeySet{signing: key, verify: map[string]*jwtKey{key.id: key}}
+}
+
+// LoadKeySet reads an RSA or Ed25519 private key from signingKeyFile and any
+// number of additional verification keys (public or private PEM) from
+// verificationKeyFiles.
+func LoadKeySet(signingKeyFile string, verificationKeyFiles []string) (*KeySet, error) {
+	signing, err := loadKeyFile(signingKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	if signing.signKey == nil {
+		return nil, fmt.Errorf("%s: signing key must be a private key", signingKeyFile)
+	}
+
+	ks := &KeySet{signing: signing, verify: map[string]*jwtKey{signing.id: signing}}
+	for _, path := range verificationKeyFiles {
+		key, err := loadKeyFile(path)
+		if err != nil {
+			return nil, err
+		}
+		key.signKey = nil // verification keys never sign, even when given as a private key
+		ks.verify[key.id] = key
+	}
+
+	return ks, nil
+}
+
+// SigningKeyID returns the kid stamped on newly issued tokens.
+func (ks *KeySet) SigningKeyID() string {
+	return ks.signing.id
+}
+
+// JWKS returns the public verification keys, signing key first.
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	if ks.signing.jwk != nil {
+		set.Keys = append(set.Keys, *ks.signing.jwk)
+	}
+	ids := make([]string, 0, len(ks.verify))
+	for id := range ks.verify {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if key := ks.verify[id]; id != ks.signing.id && key.jwk != nil {
+			set.Keys = append(set.Keys, *key.jwk)
+		}
+	}
+	return set
+}
+
+// sign signs claims with the current signing key and sets the kid header.
+func (ks *KeySet) sign(claims Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signing.method, claims)
+	token.Header["kid"] = ks.signing.id
+	return token.SignedString(ks.signing.signKey)
+}
+
+// keyFunc selects the verification key named by the token's kid header and
+// rejects tokens whose alg does not match that key.
+func (ks *KeySet) keyFunc(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("token has no kid header")
+	}
+
+	key, ok := ks.verify[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if t.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q for key %q", t.Method.Alg(), kid)
+	}
+
+	return key.verifyKey, nil
+}
+
+// algorithms lists the algs of every verification key, for jwt.WithValidMethods.
+func (ks *KeySet) algorithms() []string {
+	seen := map[string]bool{}
+	var algs []string
+	for _, key := range ks.verify {
+		if alg := key.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			algs = append(algs, alg)
+		}
+	}
+	return algs
+}
+
+func loadKeyFile(path string) (*jwtKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading key file: %w", err)
+	}
+
+	key, err := parseKeyPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// parseKeyPEM accepts PKCS#8 and PKCS#1 private keys and PKIX and PKCS#1
+// public keys holding an RSA or Ed25519 key.
+func parseKeyPEM(data []byte) (*jwtKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", block.Type, err)
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key, err := rsaKey(&k.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		key.signKey = k
+		return key, nil
+	case *rsa.PublicKey:
+		return rsaKey(k)
+	case ed25519.PrivateKey:
+		key := ed25519Key(k.Public().(ed25519.PublicKey))
+		key.signKey = k
+		return key, nil
+	case ed25519.PublicKey:
+		return ed25519Key(k), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T (want RSA or Ed25519)", parsed)
+	}
+}
+
+func rsaKey(pub *rsa.PublicKey) (*jwtKey, error) {
+	if pub.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("RSA key is %d bits, want at least %d", pub.N.BitLen(), minRSABits)
+	}
+
+	n := base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	kid := thumbprint(fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, e, n))
+
+	return &jwtKey{
+		id:        kid,
+		method:    jwt.SigningMethodRS256,
+		verifyKey: pub,
+		jwk:       &JWK{Kty: "RSA", Kid: kid, Use: "sig", Alg: "RS256", N: n, E: e},
+	}, nil
+}
+
+func ed25519Key(pub ed25519.PublicKey) *jwtKey {
+	x := base64.RawURLEncoding.EncodeToString(pub)
+	kid := thumbprint(fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":"%s"}`, x))
+
+	return &jwtKey{
+		id:        kid,
+		method:    jwt.SigningMethodEdDSA,
+		verifyKey: pub,
+		jwk:       &JWK{Kty: "OKP", Kid: kid, Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: x},
+	}
+}
+
+// thumbprint hashes the canonical JWK members (RFC 7638).
+func thumbprint(canonical string) string {
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
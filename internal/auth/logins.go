@@ -0,0 +1,61 @@
+package auth
+
+import "errors"
+
+// Sign-in methods reported to a LoginRecorder.
+const (
+	LoginMethodPassword  = "password"
+	LoginMethodMFA       = "mfa" // second factor after an MFA challenge
+	LoginMethodMagicLink = "magic_link"
+	LoginMethodOIDC      = "oidc"
+)
+
+// Sign-in outcomes reported to a LoginRecorder.
+const (
+	LoginSucceeded     = "success"
+	LoginMFARequired   = "mfa_required" // first factor passed; see LoginMethodMFA
+	LoginRejected      = "rejected"     // wrong credentials, code, link or provider response
+	LoginThrottled     = "throttled"
+	LoginAccountLocked = "account_locked" // locked, deleted or pending a forced reset
+	LoginError         = "error"
+)
+
+// LoginRecorder observes the outcome of every sign-in attempt, e.g. to count
+// them as metrics. It must be safe for concurrent use and return promptly.
+type LoginRecorder interface {
+	RecordLogin(method, outcome string)
+}
+
+// recordLogin reports a sign-in attempt to the configured LoginRecorder.
+func (s *svc) recordLogin(method string, mfaRequired bool, err error) {
+	if s.cfg.Logins != nil {
+		s.cfg.Logins.RecordLogin(method, loginOutcome(mfaRequired, err))
+	}
+}
+
+// loginOutcome classifies the result of a sign-in method.
+func loginOutcome(mfaRequired bool, err error) string {
+	var locked *LoginLockedError
+	switch {
+	case err == nil && mfaRequired:
+		return LoginMFARequired
+	case err == nil:
+		return LoginSucceeded
+	case errors.As(err, &locked):
+		return LoginThrottled
+	case errors.Is(err, ErrAccountLocked), errors.Is(err, ErrPasswordResetRequired):
+		return LoginAccountLocked
+	case errors.Is(err, ErrInvalidCredentials),
+		errors.Is(err, ErrInvalidTOTPCode),
+		errors.Is(err, ErrInvalidMFAChallenge),
+		errors.Is(err, ErrInvalidMagicLink),
+		errors.Is(err, ErrMagicLinkWrongBrowser),
+		errors.Is(err, ErrUnknownOIDCProvider),
+		errors.Is(err, ErrInvalidOIDCState),
+		errors.Is(err, ErrOIDCLoginFailed),
+		errors.Is(err, ErrOIDCEmailNotVerified):
+		return LoginRejected
+	default:
+		return LoginError
+	}
+}
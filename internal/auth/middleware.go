@@ -2,7 +2,7 @@ package auth
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -14,13 +14,32 @@ import (
 // contextKey is a private type for context keys in the auth package.
 type contextKey string
 
-// ContextKeyUserID is the key used to store the authenticated userID in the request context.
-const ContextKeyUserID contextKey = "userID"
+const (
+	// ContextKeyUserID is the key used to store the authenticated userID in the request context.
+	ContextKeyUserID contextKey = "userID"
+
+	// ContextKeyClaims is the key used to store the verified *Claims in the request context.
+	ContextKeyClaims contextKey = "claims"
+)
+
+// ClaimsFromContext returns the access token claims stored by RequireAuth.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(ContextKeyClaims).(*Claims)
+	return claims, ok
+}
+
+// RequireAuth returns a middleware that validates the Bearer JWT against keys, checks it
+// against the revocation store and injects the userID and claims into the
+// request context. Rejects requests with 401 if the token is missing,
+// malformed, expired or revoked.
+//
+// When apiKeys is non-nil a Bearer API key is accepted too; the userID and
+// the *APIKey are injected instead of claims, and RequireScope limits what
+// the key may do. With a nil apiKeys, API keys are refused with 403 — use
+// that for endpoints that need a real user session.
+func RequireAuth(keys *KeySet, revocations *RevocationStore, apiKeys APIKeyAuthenticator) func(http.Handler) http.Handler {
+	parser := jwt.NewParser(jwt.WithValidMethods(keys.algorithms()))
 
-// RequireAuth returns a middleware that validates the Bearer JWT and injects
-// the userID into the request context. Rejects requests with 401 if the token
-// is missing, malformed, or expired.
-func RequireAuth(secret string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			header := r.Header.Get("Authorization")
@@ -31,19 +50,47 @@ func RequireAuth(secret string) func(http.Handler) http.Handler {
 
 			tokenStr := strings.TrimPrefix(header, "Bearer ")
 
-			claims := &Claims{}
-			token, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (any, error) {
-				if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+			if strings.HasPrefix(tokenStr, apiKeyPrefix) {
+				if apiKeys == nil {
+					jsonutil.Write(w, http.StatusForbidden, map[string]string{"error": "api keys are not accepted for this endpoint"})
+					return
+				}
+
+				key, err := apiKeys.AuthenticateAPIKey(r.Context(), tokenStr)
+				if err != nil {
+					if errors.Is(err, ErrInvalidAPIKey) {
+						jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+						return
+					}
+					jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to verify api key"})
+					return
 				}
-				return []byte(secret), nil
-			})
+
+				ctx := context.WithValue(r.Context(), ContextKeyUserID, key.UserID)
+				ctx = context.WithValue(ctx, ContextKeyAPIKey, &key)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			claims := &Claims{}
+			// the key is selected by the token's kid header
+			token, err := parser.ParseWithClaims(tokenStr, claims, keys.keyFunc)
 			if err != nil || !token.Valid {
 				jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "invalid or expired token"})
 				return
 			}
 
+			if err := revocations.Check(r.Context(), claims); err != nil {
+				if errors.Is(err, ErrTokenRevoked) {
+					jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "token has been revoked"})
+					return
+				}
+				jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to verify token"})
+				return
+			}
+
 			ctx := context.WithValue(r.Context(), ContextKeyUserID, claims.UserID)
+			ctx = context.WithValue(ctx, ContextKeyClaims, claims)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// oidcStateCookie binds a started login to the browser that started it, so a
+// callback URL cannot be replayed in someone else's browser (login CSRF).
+const oidcStateCookie = "oidc_state"
+
+// defaultOIDCScopes are requested when a provider configures none.
+var defaultOIDCScopes = []string{oidc.ScopeOpenID, "email", "profile"}
+
+// oidcProviderName restricts provider names to what is safe in a URL path
+// segment and a cookie path.
+var oidcProviderName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// OIDCProviderConfig configures one OpenID Connect identity provider.
+type OIDCProviderConfig struct {
+	Name         string   // path segment in /auth/oidc/{provider}/...
+	Issuer       string   // discovery base URL; must equal the ID token's iss
+	ClientID     string   // also the expected ID token audience
+	ClientSecret string   // empty for public clients, which rely on PKCE alone
+	RedirectURL  string   // this API's callback URL, as registered with the provider
+	Scopes       []string // "openid" is always requested
+}
+
+// OIDCProviders is the set of configured identity providers. Discovery runs
+// on first use and is retried until it succeeds, so the API starts even while
+// a provider is unreachable.
+type OIDCProviders struct {
+	providers map[string]*oidcProvider // fixed after construction
+}
+
+type oidcProvider struct {
+	cfg OIDCProviderConfig
+
+	mu       sync.Mutex
+	oauth    *oauth2.Config // nil until discovery succeeded
+	verifier *oidc.IDTokenVerifier
+}
+
+// oidcClaims are the ID token claims used to find, link or create the user.
+type oidcClaims struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+}
+
+// NewOIDCProviders validates configs without contacting the providers.
+func NewOIDCProviders(configs []OIDCProviderConfig) (*OIDCProviders, error) {
+	p := &OIDCProviders{providers: make(map[string]*oidcProvider, len(configs))}
+	for _, cfg := range configs {
+		if !oidcProviderName.MatchString(cfg.Name) {
+			return nil, fmt.Errorf("oidc provider name %q must be lowercase letters, digits, '-' or '_'", cfg.Name)
+		}
+		if cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+			return nil, fmt.Errorf("oidc provider %q: issuer, client id and redirect url are required", cfg.Name)
+		}
+		if _, dup := p.providers[cfg.Name]; dup {
+			return nil, fmt.Errorf("oidc provider %q configured twice", cfg.Name)
+		}
+
+		switch {
+		case len(cfg.Scopes) == 0:
+			cfg.Scopes = defaultOIDCScopes
+		case !slices.Contains(cfg.Scopes, oidc.ScopeOpenID):
+			cfg.Scopes = append([]string{oidc.ScopeOpenID}, cfg.Scopes...)
+		}
+		p.providers[cfg.Name] = &oidcProvider{cfg: cfg}
+	}
+	return p, nil
+}
+
+// Names lists the configured providers, sorted.
+func (p *OIDCProviders) Names() []string {
+	if p == nil {
+		return nil
+	}
+	names := make([]string, 0, len(p.providers))
+	for name := range p.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// get returns the named provider, running discovery if it has not succeeded yet.
+func (p *OIDCProviders) get(ctx context.Context, name string) (*oidcProvider, error) {
+	if p == nil {
+		return nil, ErrUnknownOIDCProvider
+	}
+	op, ok := p.providers[name]
+	if !ok {
+		return nil, ErrUnknownOIDCProvider
+	}
+
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	if op.oauth != nil {
+		return op, nil
+	}
+
+	discovered, err := oidc.NewProvider(ctx, op.cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("discovering oidc provider %q: %w", name, err)
+	}
+	op.oauth = &oauth2.Config{
+		ClientID:     op.cfg.ClientID,
+		ClientSecret: op.cfg.ClientSecret,
+		Endpoint:     discovered.Endpoint(),
+		RedirectURL:  op.cfg.RedirectURL,
+		Scopes:       op.cfg.Scopes,
+	}
+	op.verifier = discovered.Verifier(&oidc.Config{ClientID: op.cfg.ClientID})
+	return op, nil
+}
+
+// authRequest builds the authorization URL for state with a fresh nonce and
+// PKCE verifier. Only the verifier's S256 challenge is put in the URL.
+func (op *oidcProvider) authRequest(state string) (url, nonce, verifier string, err error) {
+	nonce, _, err = newOpaqueToken()
+	if err != nil {
+		return "", "", "", fmt.Errorf("generating nonce: %w", err)
+	}
+	verifier = oauth2.GenerateVerifier()
+
+	url = op.oauth.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+	return url, nonce, verifier, nil
+}
+
+// exchange redeems code with the login's PKCE verifier and verifies the
+// returned ID token: signature, issuer, audience, expiry and nonce.
+func (op *oidcProvider) exchange(ctx context.Context, code string, login OIDCLoginState) (oidcClaims, error) {
+	token, err := op.oauth.Exchange(ctx, code, oauth2.VerifierOption(login.CodeVerifier))
+	if err != nil {
+		return oidcClaims{}, fmt.Errorf("exchanging code: %w", err)
+	}
+
+	raw, ok := token.Extra("id_token").(string)
+	if !ok || raw == "" {
+		return oidcClaims{}, errors.New("token response has no id_token")
+	}
+
+	idToken, err := op.verifier.Verify(ctx, raw)
+	if err != nil {
+		return oidcClaims{}, fmt.Errorf("verifying id token: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(login.Nonce)) != 1 {
+		return oidcClaims{}, errors.New("id token nonce does not match")
+	}
+
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return oidcClaims{}, fmt.Errorf("decoding id token claims: %w", err)
+	}
+	return claims, nil
+}
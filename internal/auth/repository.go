@@ -3,8 +3,11 @@ package auth
 import (
 	"context"
 	"errors"
+	"time"
 
+	"github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql"
 	repo "github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql/sqlc"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -25,7 +28,7 @@ func (r *postgresAuthRepository) CreateUser(ctx context.Context, params CreateUs
 		profilePic = pgtype.Text{String: params.ProfilePicture, Valid: true}
 	}
 
-	row, err := r.queries.CreateUser(ctx, repo.CreateUserParams{
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).CreateUser(ctx, repo.CreateUserParams{
 		ID:             params.ID,
 		Name:           params.Name,
 		Email:          params.Email,
@@ -40,28 +43,704 @@ func (r *postgresAuthRepository) CreateUser(ctx context.Context, params CreateUs
 		return User{}, err
 	}
 
-	return User{
-		ID:             row.ID,
-		Name:           row.Name,
-		Email:          row.Email,
-		Password:       row.Password,
-		ProfilePicture: row.ProfilePicture.String,
-		CreatedAt:      row.CreatedAt.Time.String(),
-	}, nil
+	return toUser(row), nil
 }
 
 func (r *postgresAuthRepository) GetUserByEmail(ctx context.Context, email string) (User, error) {
-	row, err := r.queries.GetUserByEmail(ctx, email)
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetUserByEmail(ctx, email)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return User{}, ErrUserNotFound
+		}
 		return User{}, err
 	}
 
-	return User{
-		ID:             row.ID,
-		Name:           row.Name,
-		Email:          row.Email,
-		Password:       row.Password,
-		ProfilePicture: row.ProfilePicture.String,
-		CreatedAt:      row.CreatedAt.Time.String(),
+	return toUser(row), nil
+}
+
+func (r *postgresAuthRepository) GetUserByID(ctx context.Context, id string) (User, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return User{}, ErrUserNotFound
+		}
+		return User{}, err
+	}
+
+	return toUser(row), nil
+}
+
+func (r *postgresAuthRepository) CreateRefreshToken(ctx context.Context, params CreateRefreshTokenParams) error {
+	_, err := postgresql.QueriesFromContext(ctx, r.queries).CreateRefreshToken(ctx, repo.CreateRefreshTokenParams{
+		ID:        params.ID,
+		UserID:    params.UserID,
+		FamilyID:  params.FamilyID,
+		TokenHash: params.TokenHash,
+		ExpiresAt: pgtype.Timestamptz{Time: params.ExpiresAt, Valid: true},
+	})
+	return err
+}
+
+func (r *postgresAuthRepository) GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (RefreshToken, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetRefreshTokenByHashForUpdate(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return RefreshToken{}, ErrInvalidRefreshToken
+		}
+		return RefreshToken{}, err
+	}
+
+	return RefreshToken{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		FamilyID:  row.FamilyID,
+		ExpiresAt: row.ExpiresAt.Time,
+		UsedAt:    row.UsedAt.Time,
+		RevokedAt: row.RevokedAt.Time,
+	}, nil
+}
+
+func (r *postgresAuthRepository) MarkRefreshTokenUsed(ctx context.Context, id string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).MarkRefreshTokenUsed(ctx, id)
+}
+
+func (r *postgresAuthRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).RevokeRefreshTokenFamily(ctx, familyID)
+}
+
+func (r *postgresAuthRepository) RevokeUserRefreshTokens(ctx context.Context, userID string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).RevokeUserRefreshTokens(ctx, userID)
+}
+
+func (r *postgresAuthRepository) CreateSession(ctx context.Context, params CreateSessionParams) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).CreateSession(ctx, repo.CreateSessionParams{
+		ID:         params.ID,
+		UserID:     params.UserID,
+		UserAgent:  params.UserAgent,
+		Ip:         params.IP,
+		DeviceName: params.DeviceName,
+	})
+}
+
+func (r *postgresAuthRepository) TouchSession(ctx context.Context, id string) (bool, error) {
+	n, err := postgresql.QueriesFromContext(ctx, r.queries).TouchSession(ctx, id)
+	return n == 1, err
+}
+
+func (r *postgresAuthRepository) RefreshSession(ctx context.Context, id, ip string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).RefreshSession(ctx, repo.RefreshSessionParams{ID: id, Ip: ip})
+}
+
+func (r *postgresAuthRepository) ListSessions(ctx context.Context, userID string, since time.Time) ([]Session, error) {
+	rows, err := postgresql.QueriesFromContext(ctx, r.queries).ListUserSessions(ctx, repo.ListUserSessionsParams{
+		UserID:     userID,
+		LastSeenAt: pgtype.Timestamptz{Time: since, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]Session, 0, len(rows))
+	for _, row := range rows {
+		sessions = append(sessions, Session{
+			ID:         row.ID,
+			UserID:     row.UserID,
+			UserAgent:  row.UserAgent,
+			IP:         row.Ip,
+			DeviceName: row.DeviceName,
+			CreatedAt:  row.CreatedAt.Time,
+			LastSeenAt: row.LastSeenAt.Time,
+		})
+	}
+	return sessions, nil
+}
+
+func (r *postgresAuthRepository) RevokeSession(ctx context.Context, id string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).RevokeSession(ctx, id)
+}
+
+func (r *postgresAuthRepository) RevokeUserSession(ctx context.Context, id, userID string) error {
+	n, err := postgresql.QueriesFromContext(ctx, r.queries).RevokeUserSession(ctx, repo.RevokeUserSessionParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+func (r *postgresAuthRepository) RevokeUserSessions(ctx context.Context, userID string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).RevokeUserSessions(ctx, userID)
+}
+
+func (r *postgresAuthRepository) RevokeAccessToken(ctx context.Context, jti, userID string, expiresAt time.Time) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).RevokeAccessToken(ctx, repo.RevokeAccessTokenParams{
+		Jti:       jti,
+		UserID:    userID,
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+}
+
+func (r *postgresAuthRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return postgresql.QueriesFromContext(ctx, r.queries).IsAccessTokenRevoked(ctx, jti)
+}
+
+func (r *postgresAuthRepository) DeleteExpiredRevokedTokens(ctx context.Context) (int64, error) {
+	return postgresql.QueriesFromContext(ctx, r.queries).DeleteExpiredRevokedTokens(ctx)
+}
+
+func (r *postgresAuthRepository) GetTokenVersion(ctx context.Context, userID string) (int32, error) {
+	version, err := postgresql.QueriesFromContext(ctx, r.queries).GetUserTokenVersion(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrTokenRevoked
+	}
+	return version, err
+}
+
+func (r *postgresAuthRepository) IncrementTokenVersion(ctx context.Context, userID string) (int32, error) {
+	return postgresql.QueriesFromContext(ctx, r.queries).IncrementUserTokenVersion(ctx, userID)
+}
+
+func (r *postgresAuthRepository) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).UpdateUserPassword(ctx, repo.UpdateUserPasswordParams{
+		ID:       userID,
+		Password: passwordHash,
+	})
+}
+
+func (r *postgresAuthRepository) LockUser(ctx context.Context, userID string) error {
+	return userAffected(postgresql.QueriesFromContext(ctx, r.queries).LockUser(ctx, userID))
+}
+
+func (r *postgresAuthRepository) UnlockUser(ctx context.Context, userID string) error {
+	return userAffected(postgresql.QueriesFromContext(ctx, r.queries).UnlockUser(ctx, userID))
+}
+
+func (r *postgresAuthRepository) RequirePasswordReset(ctx context.Context, userID string) error {
+	return userAffected(postgresql.QueriesFromContext(ctx, r.queries).RequireUserPasswordReset(ctx, userID))
+}
+
+func (r *postgresAuthRepository) SoftDeleteUser(ctx context.Context, userID string) (time.Time, error) {
+	deletedAt, err := postgresql.QueriesFromContext(ctx, r.queries).SoftDeleteUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, ErrUserNotFound
+		}
+		return time.Time{}, err
+	}
+	return deletedAt.Time, nil
+}
+
+func (r *postgresAuthRepository) RestoreUser(ctx context.Context, userID string) error {
+	purgingAt, err := postgresql.QueriesFromContext(ctx, r.queries).RestoreUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if purgingAt.Valid {
+		return ErrAccountPurging
+	}
+	return nil
+}
+
+func (r *postgresAuthRepository) CreateAuditEntry(ctx context.Context, id, actorID, action, targetUserID string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).CreateAdminAuditEntry(ctx, repo.CreateAdminAuditEntryParams{
+		ID:           id,
+		ActorID:      actorID,
+		Action:       action,
+		TargetUserID: targetUserID,
+	})
+}
+
+// userAffected maps an update that matched no user onto ErrUserNotFound.
+func userAffected(n int64, err error) error {
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *postgresAuthRepository) CreatePasswordResetToken(ctx context.Context, params CreatePasswordResetTokenParams) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).CreatePasswordResetToken(ctx, repo.CreatePasswordResetTokenParams{
+		ID:        params.ID,
+		UserID:    params.UserID,
+		TokenHash: params.TokenHash,
+		ExpiresAt: pgtype.Timestamptz{Time: params.ExpiresAt, Valid: true},
+	})
+}
+
+func (r *postgresAuthRepository) GetPasswordResetTokenForUpdate(ctx context.Context, tokenHash string) (PasswordResetToken, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetPasswordResetTokenByHashForUpdate(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PasswordResetToken{}, ErrInvalidResetToken
+		}
+		return PasswordResetToken{}, err
+	}
+
+	return PasswordResetToken{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		ExpiresAt: row.ExpiresAt.Time,
+		UsedAt:    row.UsedAt.Time,
+	}, nil
+}
+
+func (r *postgresAuthRepository) ConsumePasswordResetTokens(ctx context.Context, userID string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).ConsumeUserPasswordResetTokens(ctx, userID)
+}
+
+func (r *postgresAuthRepository) MarkEmailVerified(ctx context.Context, userID string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).MarkUserEmailVerified(ctx, userID)
+}
+
+func (r *postgresAuthRepository) IsEmailVerified(ctx context.Context, userID string) (bool, error) {
+	verifiedAt, err := postgresql.QueriesFromContext(ctx, r.queries).GetUserEmailVerifiedAt(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return verifiedAt.Valid, nil
+}
+
+func (r *postgresAuthRepository) CreateEmailVerificationToken(ctx context.Context, params CreateEmailVerificationTokenParams) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).CreateEmailVerificationToken(ctx, repo.CreateEmailVerificationTokenParams{
+		ID:        params.ID,
+		UserID:    params.UserID,
+		TokenHash: params.TokenHash,
+		ExpiresAt: pgtype.Timestamptz{Time: params.ExpiresAt, Valid: true},
+	})
+}
+
+func (r *postgresAuthRepository) GetEmailVerificationTokenForUpdate(ctx context.Context, tokenHash string) (EmailVerificationToken, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetEmailVerificationTokenByHashForUpdate(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return EmailVerificationToken{}, ErrInvalidVerificationToken
+		}
+		return EmailVerificationToken{}, err
+	}
+
+	return EmailVerificationToken{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		ExpiresAt: row.ExpiresAt.Time,
+		UsedAt:    row.UsedAt.Time,
+	}, nil
+}
+
+func (r *postgresAuthRepository) ConsumeEmailVerificationTokens(ctx context.Context, userID string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).ConsumeUserEmailVerificationTokens(ctx, userID)
+}
+
+func (r *postgresAuthRepository) GetVerificationSendStats(ctx context.Context, userID string, since time.Time) (VerificationSendStats, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetEmailVerificationSendStats(ctx, repo.GetEmailVerificationSendStatsParams{
+		UserID:    userID,
+		CreatedAt: pgtype.Timestamptz{Time: since, Valid: true},
+	})
+	if err != nil {
+		return VerificationSendStats{}, err
+	}
+
+	return VerificationSendStats{
+		Sent:       int(row.Sent),
+		LastSentAt: row.LastSentAt.Time,
+	}, nil
+}
+
+func (r *postgresAuthRepository) CreateMagicLinkToken(ctx context.Context, params CreateMagicLinkTokenParams) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).CreateMagicLinkToken(ctx, repo.CreateMagicLinkTokenParams{
+		ID:          params.ID,
+		UserID:      params.UserID,
+		TokenHash:   params.TokenHash,
+		BrowserHash: params.BrowserHash,
+		ExpiresAt:   pgtype.Timestamptz{Time: params.ExpiresAt, Valid: true},
+	})
+}
+
+func (r *postgresAuthRepository) GetMagicLinkTokenForUpdate(ctx context.Context, tokenHash string) (MagicLinkToken, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetMagicLinkTokenByHashForUpdate(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return MagicLinkToken{}, ErrInvalidMagicLink
+		}
+		return MagicLinkToken{}, err
+	}
+
+	return MagicLinkToken{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		BrowserHash: row.BrowserHash,
+		ExpiresAt:   row.ExpiresAt.Time,
+		UsedAt:      row.UsedAt.Time,
+	}, nil
+}
+
+func (r *postgresAuthRepository) ConsumeMagicLinkTokens(ctx context.Context, userID string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).ConsumeUserMagicLinkTokens(ctx, userID)
+}
+
+func (r *postgresAuthRepository) CountMagicLinksSince(ctx context.Context, userID string, since time.Time) (int, error) {
+	n, err := postgresql.QueriesFromContext(ctx, r.queries).CountMagicLinkTokensSince(ctx, repo.CountMagicLinkTokensSinceParams{
+		UserID:    userID,
+		CreatedAt: pgtype.Timestamptz{Time: since, Valid: true},
+	})
+	return int(n), err
+}
+
+func (r *postgresAuthRepository) UpdateEmail(ctx context.Context, userID, email string) error {
+	err := postgresql.QueriesFromContext(ctx, r.queries).UpdateUserEmail(ctx, repo.UpdateUserEmailParams{ID: userID, Email: email})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrEmailTaken
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *postgresAuthRepository) CreateEmailChangeToken(ctx context.Context, params CreateEmailChangeTokenParams) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).CreateEmailChangeToken(ctx, repo.CreateEmailChangeTokenParams{
+		ID:        params.ID,
+		UserID:    params.UserID,
+		NewEmail:  params.NewEmail,
+		TokenHash: params.TokenHash,
+		ExpiresAt: pgtype.Timestamptz{Time: params.ExpiresAt, Valid: true},
+	})
+}
+
+func (r *postgresAuthRepository) GetEmailChangeTokenForUpdate(ctx context.Context, tokenHash string) (EmailChangeToken, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetEmailChangeTokenByHashForUpdate(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return EmailChangeToken{}, ErrInvalidEmailChangeToken
+		}
+		return EmailChangeToken{}, err
+	}
+
+	return EmailChangeToken{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		NewEmail:  row.NewEmail,
+		ExpiresAt: row.ExpiresAt.Time,
+		UsedAt:    row.UsedAt.Time,
+	}, nil
+}
+
+func (r *postgresAuthRepository) ConsumeEmailChangeTokens(ctx context.Context, userID string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).ConsumeUserEmailChangeTokens(ctx, userID)
+}
+
+func (r *postgresAuthRepository) CountEmailChangesSince(ctx context.Context, userID string, since time.Time) (int, error) {
+	n, err := postgresql.QueriesFromContext(ctx, r.queries).CountEmailChangeTokensSince(ctx, repo.CountEmailChangeTokensSinceParams{
+		UserID:    userID,
+		CreatedAt: pgtype.Timestamptz{Time: since, Valid: true},
+	})
+	return int(n), err
+}
+
+func (r *postgresAuthRepository) UpsertPendingTOTP(ctx context.Context, userID string, secretEncrypted []byte) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).UpsertPendingUserTOTP(ctx, repo.UpsertPendingUserTOTPParams{
+		UserID:          userID,
+		SecretEncrypted: secretEncrypted,
+	})
+}
+
+func (r *postgresAuthRepository) GetTOTP(ctx context.Context, userID string) (TOTPEnrollment, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetUserTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return TOTPEnrollment{}, ErrTOTPNotEnrolled
+		}
+		return TOTPEnrollment{}, err
+	}
+	return toTOTPEnrollment(row), nil
+}
+
+func (r *postgresAuthRepository) GetTOTPForUpdate(ctx context.Context, userID string) (TOTPEnrollment, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetUserTOTPForUpdate(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return TOTPEnrollment{}, ErrTOTPNotEnrolled
+		}
+		return TOTPEnrollment{}, err
+	}
+	return toTOTPEnrollment(row), nil
+}
+
+func (r *postgresAuthRepository) ConfirmTOTP(ctx context.Context, userID string, step int64) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).ConfirmUserTOTP(ctx, repo.ConfirmUserTOTPParams{
+		UserID:       userID,
+		LastUsedStep: step,
+	})
+}
+
+func (r *postgresAuthRepository) UpdateTOTPLastUsedStep(ctx context.Context, userID string, step int64) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).UpdateUserTOTPLastUsedStep(ctx, repo.UpdateUserTOTPLastUsedStepParams{
+		UserID:       userID,
+		LastUsedStep: step,
+	})
+}
+
+func (r *postgresAuthRepository) DeleteRecoveryCodes(ctx context.Context, userID string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).DeleteUserRecoveryCodes(ctx, userID)
+}
+
+func (r *postgresAuthRepository) CreateRecoveryCode(ctx context.Context, id, userID, codeHash string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).CreateRecoveryCode(ctx, repo.CreateRecoveryCodeParams{
+		ID:       id,
+		UserID:   userID,
+		CodeHash: codeHash,
+	})
+}
+
+func (r *postgresAuthRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	n, err := postgresql.QueriesFromContext(ctx, r.queries).UseRecoveryCode(ctx, repo.UseRecoveryCodeParams{
+		UserID:   userID,
+		CodeHash: codeHash,
+	})
+	return n == 1, err
+}
+
+func (r *postgresAuthRepository) CreateMFAChallenge(ctx context.Context, params CreateMFAChallengeParams) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).CreateMFAChallenge(ctx, repo.CreateMFAChallengeParams{
+		ID:        params.ID,
+		UserID:    params.UserID,
+		TokenHash: params.TokenHash,
+		ExpiresAt: pgtype.Timestamptz{Time: params.ExpiresAt, Valid: true},
+	})
+}
+
+func (r *postgresAuthRepository) GetMFAChallengeForUpdate(ctx context.Context, tokenHash string) (MFAChallenge, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetMFAChallengeByHashForUpdate(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return MFAChallenge{}, ErrInvalidMFAChallenge
+		}
+		return MFAChallenge{}, err
+	}
+
+	return MFAChallenge{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		Attempts:  int(row.Attempts),
+		ExpiresAt: row.ExpiresAt.Time,
+		UsedAt:    row.UsedAt.Time,
+	}, nil
+}
+
+func (r *postgresAuthRepository) IncrementMFAChallengeAttempts(ctx context.Context, id string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).IncrementMFAChallengeAttempts(ctx, id)
+}
+
+func (r *postgresAuthRepository) MarkMFAChallengeUsed(ctx context.Context, id string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).MarkMFAChallengeUsed(ctx, id)
+}
+
+func (r *postgresAuthRepository) GetLoginLockedUntil(ctx context.Context, keys []string) (time.Time, error) {
+	until, err := postgresql.QueriesFromContext(ctx, r.queries).GetLoginLockedUntil(ctx, keys)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return until.Time, nil
+}
+
+func (r *postgresAuthRepository) RecordLoginFailure(ctx context.Context, key string, resetBefore time.Time) (int, error) {
+	failures, err := postgresql.QueriesFromContext(ctx, r.queries).RecordLoginFailure(ctx, repo.RecordLoginFailureParams{
+		Key:         key,
+		ResetBefore: pgtype.Timestamptz{Time: resetBefore, Valid: true},
+	})
+	return int(failures), err
+}
+
+func (r *postgresAuthRepository) LockLoginKey(ctx context.Context, key string, until time.Time) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).LockLoginKey(ctx, repo.LockLoginKeyParams{
+		Key:         key,
+		LockedUntil: pgtype.Timestamptz{Time: until, Valid: true},
+	})
+}
+
+func (r *postgresAuthRepository) ClearLoginFailures(ctx context.Context, key string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).ClearLoginFailures(ctx, key)
+}
+
+func (r *postgresAuthRepository) DeleteStaleLoginThrottles(ctx context.Context, before time.Time) (int64, error) {
+	return postgresql.QueriesFromContext(ctx, r.queries).DeleteStaleLoginThrottles(ctx, pgtype.Timestamptz{Time: before, Valid: true})
+}
+
+func (r *postgresAuthRepository) CreateAPIKey(ctx context.Context, params CreateAPIKeyParams) (APIKey, error) {
+	var expiresAt pgtype.Timestamptz
+	if !params.ExpiresAt.IsZero() {
+		expiresAt = pgtype.Timestamptz{Time: params.ExpiresAt, Valid: true}
+	}
+
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).CreateAPIKey(ctx, repo.CreateAPIKeyParams{
+		ID:        params.ID,
+		UserID:    params.UserID,
+		Name:      params.Name,
+		Prefix:    params.Prefix,
+		KeyHash:   params.KeyHash,
+		Scopes:    params.Scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return APIKey{}, err
+	}
+
+	return toAPIKey(row), nil
+}
+
+func (r *postgresAuthRepository) ListAPIKeys(ctx context.Context, userID string) ([]APIKey, error) {
+	rows, err := postgresql.QueriesFromContext(ctx, r.queries).ListUserAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]APIKey, len(rows))
+	for i, row := range rows {
+		keys[i] = toAPIKey(row)
+	}
+	return keys, nil
+}
+
+func (r *postgresAuthRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (APIKey, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetAPIKeyByHash(ctx, keyHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return APIKey{}, ErrInvalidAPIKey
+		}
+		return APIKey{}, err
+	}
+
+	return toAPIKey(row), nil
+}
+
+func (r *postgresAuthRepository) TouchAPIKey(ctx context.Context, id string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).TouchAPIKey(ctx, id)
+}
+
+func (r *postgresAuthRepository) DeleteAPIKey(ctx context.Context, id, userID string) error {
+	n, err := postgresql.QueriesFromContext(ctx, r.queries).DeleteUserAPIKey(ctx, repo.DeleteUserAPIKeyParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (r *postgresAuthRepository) CreateOIDCLoginState(ctx context.Context, params CreateOIDCLoginStateParams) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).CreateOIDCLoginState(ctx, repo.CreateOIDCLoginStateParams{
+		StateHash:    params.StateHash,
+		Provider:     params.Provider,
+		Nonce:        params.Nonce,
+		CodeVerifier: params.CodeVerifier,
+		ExpiresAt:    pgtype.Timestamptz{Time: params.ExpiresAt, Valid: true},
+	})
+}
+
+func (r *postgresAuthRepository) ConsumeOIDCLoginState(ctx context.Context, stateHash string) (OIDCLoginState, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).ConsumeOIDCLoginState(ctx, stateHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return OIDCLoginState{}, ErrInvalidOIDCState
+		}
+		return OIDCLoginState{}, err
+	}
+
+	return OIDCLoginState{
+		Provider:     row.Provider,
+		Nonce:        row.Nonce,
+		CodeVerifier: row.CodeVerifier,
+		ExpiresAt:    row.ExpiresAt.Time,
 	}, nil
 }
+
+func (r *postgresAuthRepository) DeleteExpiredOIDCLoginStates(ctx context.Context) (int64, error) {
+	return postgresql.QueriesFromContext(ctx, r.queries).DeleteExpiredOIDCLoginStates(ctx)
+}
+
+func (r *postgresAuthRepository) GetUserByIdentity(ctx context.Context, provider, subject string) (User, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetUserByIdentity(ctx, repo.GetUserByIdentityParams{
+		Provider: provider,
+		Subject:  subject,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return User{}, ErrUserNotFound
+		}
+		return User{}, err
+	}
+
+	return toUser(row), nil
+}
+
+func (r *postgresAuthRepository) CreateUserIdentity(ctx context.Context, params CreateUserIdentityParams) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).CreateUserIdentity(ctx, repo.CreateUserIdentityParams{
+		Provider: params.Provider,
+		Subject:  params.Subject,
+		UserID:   params.UserID,
+		Email:    params.Email,
+	})
+}
+
+func (r *postgresAuthRepository) TouchUserIdentity(ctx context.Context, provider, subject, email string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).TouchUserIdentity(ctx, repo.TouchUserIdentityParams{
+		Provider: provider,
+		Subject:  subject,
+		Email:    email,
+	})
+}
+
+func toAPIKey(row repo.ApiKey) APIKey {
+	return APIKey{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		Name:       row.Name,
+		Prefix:     row.Prefix,
+		Scopes:     row.Scopes,
+		ExpiresAt:  row.ExpiresAt.Time,
+		LastUsedAt: row.LastUsedAt.Time,
+		CreatedAt:  row.CreatedAt.Time,
+	}
+}
+
+func toTOTPEnrollment(row repo.UserTotp) TOTPEnrollment {
+	return TOTPEnrollment{
+		UserID:          row.UserID,
+		SecretEncrypted: row.SecretEncrypted,
+		ConfirmedAt:     row.ConfirmedAt.Time,
+		LastUsedStep:    row.LastUsedStep,
+	}
+}
+
+func toUser(row repo.User) User {
+	return User{
+		ID:              row.ID,
+		Name:            row.Name,
+		Email:           row.Email,
+		Password:        row.Password,
+		ProfilePicture:  row.ProfilePicture.String,
+		CreatedAt:       row.CreatedAt.Time.String(),
+		TokenVersion:    row.TokenVersion,
+		EmailVerifiedAt: row.EmailVerifiedAt.Time,
+		Role:            row.Role,
+		LockedAt:        row.LockedAt.Time,
+		DeletedAt:       row.DeletedAt.Time,
+
+		PasswordResetRequired: row.PasswordResetRequired,
+	}
+}
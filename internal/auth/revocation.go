@@ -0,0 +1,300 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// RevocationStore decides whether an access token has been logged out.
+//
+// Postgres is the source of truth; answers are cached in-process so the hot
+// path in RequireAuth usually avoids a query. Revoked JTIs are cached until the
+// token itself expires. "Not revoked" answers, session states and token
+// versions are cached for cacheTTL, which bounds how long a logout performed
+// on another instance can go unnoticed here. Logouts performed through a
+// RevocationBatch take effect immediately once it is applied. Each session
+// lookup also records the session's last activity, so last_seen_at is at most
+// cacheTTL stale.
+//
+// The cache only moves towards "revoked": a cached token version is never
+// lowered and a session cached as inactive is never made active again, since
+// neither happens in Postgres. A lookup that read the database before a
+// logout was applied therefore cannot undo it when it finishes afterwards.
+type RevocationStore struct {
+	repo     Repository
+	cacheTTL time.Duration
+
+	mu       sync.Mutex
+	revoked  map[string]time.Time // jti → token expiry
+	allowed  map[string]time.Time // jti → cache entry expiry
+	versions map[string]cachedVersion
+	sessions map[string]cachedSession // sid → whether the session is active
+}
+
+type cachedSession struct {
+	active  bool
+	expires time.Time
+}
+
+type cachedVersion struct {
+	version int32
+	expires time.Time
+}
+
+// NewRevocationStore constructs a RevocationStore backed by repo.
+func NewRevocationStore(repo Repository, cacheTTL time.Duration) *RevocationStore {
+	return &RevocationStore{
+		repo:     repo,
+		cacheTTL: cacheTTL,
+		revoked:  make(map[string]time.Time),
+		allowed:  make(map[string]time.Time),
+		versions: make(map[string]cachedVersion),
+		sessions: make(map[string]cachedSession),
+	}
+}
+
+// Check returns ErrTokenRevoked if the token described by claims was logged
+// out individually, belongs to a revoked session or predates the user's last
+// logout-all. Tokens without a jti or sid were issued before revocation
+// existed and are rejected.
+func (s *RevocationStore) Check(ctx context.Context, claims *Claims) error {
+	if claims.ID == "" || claims.SessionID == "" {
+		return ErrTokenRevoked
+	}
+
+	revoked, err := s.isRevoked(ctx, claims.ID)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return ErrTokenRevoked
+	}
+
+	active, err := s.sessionActive(ctx, claims.SessionID)
+	if err != nil {
+		return err
+	}
+	if !active {
+		return ErrTokenRevoked
+	}
+
+	version, err := s.tokenVersion(ctx, claims.UserID)
+	if err != nil {
+		return err
+	}
+	if claims.TokenVersion < version {
+		return ErrTokenRevoked
+	}
+
+	return nil
+}
+
+// RevocationBatch records revocations in Postgres and holds back the matching
+// cache updates until Apply. Start one per transaction attempt and call Apply
+// once the transaction has committed: a rollback or retry would otherwise
+// leave the cache disagreeing with Postgres.
+type RevocationBatch struct {
+	store   *RevocationStore
+	updates []func(now time.Time)
+}
+
+// Batch starts an empty RevocationBatch.
+func (s *RevocationStore) Batch() *RevocationBatch {
+	return &RevocationBatch{store: s}
+}
+
+// Revoke records jti as revoked until expiresAt.
+func (b *RevocationBatch) Revoke(ctx context.Context, jti, userID string, expiresAt time.Time) error {
+	if err := b.store.repo.RevokeAccessToken(ctx, jti, userID, expiresAt); err != nil {
+		return err
+	}
+
+	b.updates = append(b.updates, func(time.Time) {
+		delete(b.store.allowed, jti)
+		b.store.revoked[jti] = expiresAt
+	})
+	return nil
+}
+
+// RevokeSession ends a session: every access token carrying its sid is rejected.
+func (b *RevocationBatch) RevokeSession(ctx context.Context, sessionID string) error {
+	if err := b.store.repo.RevokeSession(ctx, sessionID); err != nil {
+		return err
+	}
+
+	b.updates = append(b.updates, func(now time.Time) {
+		b.store.cacheSession(sessionID, false, now)
+	})
+	return nil
+}
+
+// BumpVersion invalidates every access token issued to userID so far.
+func (b *RevocationBatch) BumpVersion(ctx context.Context, userID string) error {
+	version, err := b.store.repo.IncrementTokenVersion(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	b.updates = append(b.updates, func(now time.Time) {
+		b.store.cacheVersion(userID, version, now)
+	})
+	return nil
+}
+
+// Apply makes the batch's revocations take effect in the cache immediately;
+// call it once the transaction has committed. Lookups still in flight cannot
+// undo them; see RevocationStore.
+func (b *RevocationBatch) Apply() {
+	if len(b.updates) == 0 {
+		return
+	}
+
+	now := time.Now()
+	b.store.mu.Lock()
+	defer b.store.mu.Unlock()
+	for _, update := range b.updates {
+		update(now)
+	}
+	b.updates = nil
+}
+
+// Prune drops expired cache entries and deletes revocation rows for tokens
+// that have expired on their own.
+func (s *RevocationStore) Prune(ctx context.Context) error {
+	now := time.Now()
+
+	s.mu.Lock()
+	for jti, exp := range s.revoked {
+		if now.After(exp) {
+			delete(s.revoked, jti)
+		}
+	}
+	for jti, exp := range s.allowed {
+		if now.After(exp) {
+			delete(s.allowed, jti)
+		}
+	}
+	for userID, v := range s.versions {
+		if now.After(v.expires) {
+			delete(s.versions, userID)
+		}
+	}
+	for sid, v := range s.sessions {
+		if now.After(v.expires) {
+			delete(s.sessions, sid)
+		}
+	}
+	s.mu.Unlock()
+
+	_, err := s.repo.DeleteExpiredRevokedTokens(ctx)
+	return err
+}
+
+// Run calls Prune every interval until ctx is cancelled.
+func (s *RevocationStore) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Prune(ctx); err != nil {
+				slog.Error("pruning revoked tokens", "error", err)
+			}
+		}
+	}
+}
+
+func (s *RevocationStore) isRevoked(ctx context.Context, jti string) (bool, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	if _, ok := s.revoked[jti]; ok {
+		s.mu.Unlock()
+		return true, nil
+	}
+	if exp, ok := s.allowed[jti]; ok && now.Before(exp) {
+		s.mu.Unlock()
+		return false, nil
+	}
+	s.mu.Unlock()
+
+	revoked, err := s.repo.IsAccessTokenRevoked(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if revoked {
+		// the real expiry is unknown here; keep it until the next cache window
+		s.revoked[jti] = now.Add(s.cacheTTL)
+	} else {
+		s.allowed[jti] = now.Add(s.cacheTTL)
+	}
+	return revoked, nil
+}
+
+func (s *RevocationStore) tokenVersion(ctx context.Context, userID string) (int32, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	if v, ok := s.versions[userID]; ok && now.Before(v.expires) {
+		s.mu.Unlock()
+		return v.version, nil
+	}
+	s.mu.Unlock()
+
+	version, err := s.repo.GetTokenVersion(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cacheVersion(userID, version, now), nil
+}
+
+func (s *RevocationStore) sessionActive(ctx context.Context, sessionID string) (bool, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	if v, ok := s.sessions[sessionID]; ok && now.Before(v.expires) {
+		s.mu.Unlock()
+		return v.active, nil
+	}
+	s.mu.Unlock()
+
+	active, err := s.repo.TouchSession(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cacheSession(sessionID, active, now), nil
+}
+
+// cacheVersion caches userID's token version, unless a higher one is cached
+// already, and returns the cached version. s.mu must be held.
+func (s *RevocationStore) cacheVersion(userID string, version int32, now time.Time) int32 {
+	if cached, ok := s.versions[userID]; ok {
+		version = max(version, cached.version)
+	}
+	s.versions[userID] = cachedVersion{version: version, expires: now.Add(s.cacheTTL)}
+	return version
+}
+
+// cacheSession caches whether a session is active, unless it is cached as
+// inactive already, and returns the cached state. s.mu must be held.
+func (s *RevocationStore) cacheSession(sessionID string, active bool, now time.Time) bool {
+	if cached, ok := s.sessions[sessionID]; ok && !cached.active {
+		active = false
+	}
+	s.sessions[sessionID] = cachedSession{active: active, expires: now.Add(s.cacheTTL)}
+	return active
+}
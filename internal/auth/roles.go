@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"net/http"
+	"slices"
+
+	jsonutil "github.com/Ajay01103/goTransactonsAPI/internal/json"
+)
+
+// Roles a user can hold. New accounts get RoleUser; promoting someone to
+// RoleAdmin is done in the database.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// RequireRole returns a middleware, mounted after RequireAuth, that rejects
+// requests with 403 unless the access token carries one of roles. Requests
+// authenticated with an API key have no role and are always rejected.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := ClaimsFromContext(r.Context())
+			if !ok || !slices.Contains(roles, claims.Role) {
+				jsonutil.Write(w, http.StatusForbidden, map[string]string{"error": "insufficient role"})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// SecretBox encrypts small secrets that must be readable again, such as TOTP
+// seeds, with AES-256-GCM. The ciphertext is bound to additional data (the
+// owning user's ID) so it cannot be moved to another row.
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+// NewSecretBox constructs a SecretBox from a 32-byte key.
+func NewSecretBox(key []byte) (*SecretBox, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("secret box key must be 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretBox{aead: aead}, nil
+}
+
+// seal returns nonce || ciphertext.
+func (b *SecretBox) seal(plaintext, additional []byte) ([]byte, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("reading random bytes: %w", err)
+	}
+	return b.aead.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+// open reverses seal. It fails if the data or additional data was tampered with.
+func (b *SecretBox) open(sealed, additional []byte) ([]byte, error) {
+	n := b.aead.NonceSize()
+	if len(sealed) < n {
+		return nil, errors.New("sealed secret too short")
+	}
+	return b.aead.Open(nil, sealed[:n], sealed[n:], additional)
+}
@@ -2,25 +2,58 @@ package auth
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/lucsky/cuid"
 	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/mailer"
+)
+
+const (
+	// mailTimeout bounds a single background email delivery.
+	mailTimeout = 30 * time.Second
+
+	// maxVerificationEmailsPerHour caps resends on top of the cooldown.
+	maxVerificationEmailsPerHour = 5
+
+	// maxMagicLinksPerHour caps login links per account; extra requests are dropped silently.
+	maxMagicLinksPerHour = 5
+
+	// maxEmailChangesPerHour caps email change confirmations per account.
+	maxEmailChangesPerHour = 5
 )
 
 type svc struct {
-	repo      Repository
-	jwtSecret string
+	repo        Repository
+	tx          TxRunner
+	revocations *RevocationStore
+	throttle    *LoginThrottle
+	mailer      mailer.Mailer
+	cfg         Config
 }
 
-// NewService wires an auth Repository and JWT secret into a Service.
-func NewService(repo Repository, jwtSecret string) Service {
-	return &svc{repo: repo, jwtSecret: jwtSecret}
+// NewService wires an auth Repository, a TxRunner for multi-step token
+// updates, the access-token RevocationStore, the LoginThrottle guarding
+// Login, a Mailer for account emails and the token Config into a Service.
+func NewService(repo Repository, tx TxRunner, revocations *RevocationStore, throttle *LoginThrottle, mailer mailer.Mailer, cfg Config) Service {
+	return &svc{repo: repo, tx: tx, revocations: revocations, throttle: throttle, mailer: mailer, cfg: cfg}
 }
 
-// Register hashes the password then delegates persistence to the repository.
+// Register validates the email, hashes the password, delegates persistence to
+// the repository and emails a verification link.
 func (s *svc) Register(ctx context.Context, input RegisterInput) (AuthResponse, error) {
+	if addr, err := mail.ParseAddress(input.Email); err != nil || addr.Address != input.Email {
+		return AuthResponse{}, ErrInvalidEmail
+	}
+
 	hashed, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return AuthResponse{}, fmt.Errorf("hashing password: %w", err)
@@ -41,47 +74,1443 @@ func (s *svc) Register(ctx context.Context, input RegisterInput) (AuthResponse,
 		return AuthResponse{}, fmt.Errorf("creating user: %w", err)
 	}
 
-	token, err := generateToken(user.ID, user.Email, s.jwtSecret)
-	if err != nil {
-		return AuthResponse{}, fmt.Errorf("generating token: %w", err)
+	// the account exists either way; a failed email can be resent later
+	if err := s.sendVerification(ctx, user); err != nil {
+		slog.ErrorContext(ctx, "sending verification email", "user_id", user.ID, "error", err)
 	}
 
-	return AuthResponse{
-		AccessToken: token,
-		User: UserPayload{
-			ID:             user.ID,
-			Name:           user.Name,
-			Email:          user.Email,
-			ProfilePicture: user.ProfilePicture,
-			CreatedAt:      user.CreatedAt,
-		},
-	}, nil
+	return s.startSession(ctx, user, input.Client)
 }
 
 // Login looks up the user by email and verifies the bcrypt password.
-func (s *svc) Login(ctx context.Context, input LoginInput) (AuthResponse, error) {
+// Each successful login starts a new refresh-token family. Accounts with TOTP
+// enabled get an MFA challenge instead of tokens; see VerifyMFA. Locked
+// accounts and accounts awaiting a forced password reset are refused.
+// Repeated failures lock the email and client IP out; see LoginThrottle.
+func (s *svc) Login(ctx context.Context, input LoginInput) (LoginResult, error) {
+	result, err := s.login(ctx, input)
+	s.recordLogin(LoginMethodPassword, result.MFA != nil, err)
+	return result, err
+}
+
+// login implements Login; Login records the outcome.
+func (s *svc) login(ctx context.Context, input LoginInput) (LoginResult, error) {
+	if err := s.throttle.Check(ctx, input.Email, input.Client.IP); err != nil {
+		return LoginResult{}, err
+	}
+
 	user, err := s.repo.GetUserByEmail(ctx, input.Email)
 	if err != nil {
-		return AuthResponse{}, fmt.Errorf("invalid credentials")
+		// burn the same bcrypt time as a real account so timing reveals nothing
+		bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(input.Password))
+		return LoginResult{}, s.loginFailed(ctx, input)
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password)); err != nil {
-		return AuthResponse{}, fmt.Errorf("invalid credentials")
+		return LoginResult{}, s.loginFailed(ctx, input)
+	}
+
+	if err := s.throttle.Succeeded(ctx, input.Email); err != nil {
+		slog.ErrorContext(ctx, "clearing login failures", "user_id", user.ID, "error", err)
+	}
+
+	// checked after the password so account state is not revealed to strangers
+	if err := checkAccountUsable(user); err != nil {
+		return LoginResult{}, err
+	}
+	if user.PasswordResetRequired {
+		return LoginResult{}, ErrPasswordResetRequired
+	}
+
+	return s.signIn(ctx, user, input.Client)
+}
+
+// signIn finishes a login whose first factor has been checked: it returns an
+// MFA challenge when the account has TOTP enabled and starts a session otherwise.
+func (s *svc) signIn(ctx context.Context, user User, client ClientInfo) (LoginResult, error) {
+	enrollment, err := s.repo.GetTOTP(ctx, user.ID)
+	if err != nil && !errors.Is(err, ErrTOTPNotEnrolled) {
+		return LoginResult{}, fmt.Errorf("loading two-factor settings: %w", err)
+	}
+	if err == nil && !enrollment.ConfirmedAt.IsZero() {
+		challenge, err := s.newMFAChallenge(ctx, user.ID)
+		if err != nil {
+			return LoginResult{}, err
+		}
+		return LoginResult{MFA: &challenge}, nil
 	}
 
-	token, err := generateToken(user.ID, user.Email, s.jwtSecret)
+	resp, err := s.startSession(ctx, user, client)
 	if err != nil {
-		return AuthResponse{}, fmt.Errorf("generating token: %w", err)
+		return LoginResult{}, err
 	}
+	return LoginResult{Auth: &resp}, nil
+}
 
-	return AuthResponse{
-		AccessToken: token,
-		User: UserPayload{
-			ID:             user.ID,
-			Name:           user.Name,
-			Email:          user.Email,
-			ProfilePicture: user.ProfilePicture,
-			CreatedAt:      user.CreatedAt,
-		},
-	}, nil
+// loginFailed records a failed login and returns the generic error for it.
+func (s *svc) loginFailed(ctx context.Context, input LoginInput) error {
+	if err := s.throttle.Failed(ctx, input.Email, input.Client.IP); err != nil {
+		slog.ErrorContext(ctx, "recording login failure", "error", err)
+	}
+	return ErrInvalidCredentials
+}
+
+// Refresh exchanges a refresh token for a new access/refresh pair. The
+// presented token is marked used and replaced by one in the same family.
+// Presenting a token that was already used is treated as theft: the whole
+// family is revoked and ErrRefreshTokenReused is returned.
+func (s *svc) Refresh(ctx context.Context, input RefreshInput) (AuthResponse, error) {
+	var (
+		resp   AuthResponse
+		reused bool
+		revs   *RevocationBatch
+	)
+
+	err := s.tx.Atomic(ctx, func(ctx context.Context) error {
+		reused = false
+		revs = s.revocations.Batch()
+
+		token, err := s.repo.GetRefreshTokenForUpdate(ctx, hashToken(input.RefreshToken))
+		if err != nil {
+			return err
+		}
+
+		if !token.RevokedAt.IsZero() {
+			return ErrInvalidRefreshToken
+		}
+
+		if !token.UsedAt.IsZero() {
+			if err := s.repo.RevokeRefreshTokenFamily(ctx, token.FamilyID); err != nil {
+				return fmt.Errorf("revoking token family: %w", err)
+			}
+			if err := revs.RevokeSession(ctx, token.FamilyID); err != nil {
+				return fmt.Errorf("revoking session: %w", err)
+			}
+			// commit the revocation; the error is reported after the transaction
+			reused = true
+			return nil
+		}
+
+		if time.Now().After(token.ExpiresAt) {
+			return ErrInvalidRefreshToken
+		}
+
+		if err := s.repo.MarkRefreshTokenUsed(ctx, token.ID); err != nil {
+			return fmt.Errorf("marking refresh token used: %w", err)
+		}
+		if err := s.repo.RefreshSession(ctx, token.FamilyID, input.Client.IP); err != nil {
+			return fmt.Errorf("updating session: %w", err)
+		}
+
+		user, err := s.repo.GetUserByID(ctx, token.UserID)
+		if err != nil {
+			return fmt.Errorf("loading user: %w", err)
+		}
+
+		resp, err = s.issueTokens(ctx, user, token.FamilyID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidRefreshToken) || errors.Is(err, ErrAccountLocked) {
+			return AuthResponse{}, err
+		}
+		return AuthResponse{}, fmt.Errorf("refreshing token: %w", err)
+	}
+	revs.Apply()
+	if reused {
+		return AuthResponse{}, ErrRefreshTokenReused
+	}
+
+	return resp, nil
+}
+
+// Logout revokes the presented access token and the refresh-token family it
+// was issued with, ending that session only.
+func (s *svc) Logout(ctx context.Context, input LogoutInput) error {
+	var revs *RevocationBatch
+	err := s.tx.Atomic(ctx, func(ctx context.Context) error {
+		revs = s.revocations.Batch()
+		if input.SessionID != "" {
+			if err := s.repo.RevokeRefreshTokenFamily(ctx, input.SessionID); err != nil {
+				return fmt.Errorf("revoking token family: %w", err)
+			}
+			if err := revs.RevokeSession(ctx, input.SessionID); err != nil {
+				return fmt.Errorf("revoking session: %w", err)
+			}
+		}
+		return revs.Revoke(ctx, input.TokenID, input.UserID, input.ExpiresAt)
+	})
+	if err != nil {
+		return fmt.Errorf("logging out: %w", err)
+	}
+	revs.Apply()
+	return nil
+}
+
+// ListSessions returns the user's active sessions. Sessions idle for longer
+// than the refresh-token lifetime can no longer be resumed and are left out.
+func (s *svc) ListSessions(ctx context.Context, userID, currentID string) (SessionListResponse, error) {
+	sessions, err := s.repo.ListSessions(ctx, userID, time.Now().Add(-s.cfg.RefreshTokenTTL))
+	if err != nil {
+		return SessionListResponse{}, fmt.Errorf("listing sessions: %w", err)
+	}
+
+	resp := SessionListResponse{Sessions: make([]SessionPayload, 0, len(sessions))}
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, toSessionPayload(session, currentID))
+	}
+	return resp, nil
+}
+
+// RevokeSession signs one of the user's sessions out: its refresh tokens stop
+// working immediately and its access tokens are rejected by RequireAuth.
+func (s *svc) RevokeSession(ctx context.Context, userID, id string) error {
+	var revs *RevocationBatch
+	err := s.tx.Atomic(ctx, func(ctx context.Context) error {
+		revs = s.revocations.Batch()
+		if err := s.repo.RevokeUserSession(ctx, id, userID); err != nil {
+			return err
+		}
+		if err := s.repo.RevokeRefreshTokenFamily(ctx, id); err != nil {
+			return fmt.Errorf("revoking token family: %w", err)
+		}
+		return revs.RevokeSession(ctx, id)
+	})
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return err
+		}
+		return fmt.Errorf("revoking session: %w", err)
+	}
+	revs.Apply()
+	return nil
+}
+
+// LogoutAll revokes every refresh token of the user and bumps their token
+// version, so all access tokens issued so far are rejected.
+func (s *svc) LogoutAll(ctx context.Context, userID string) error {
+	var revs *RevocationBatch
+	if err := s.tx.Atomic(ctx, func(ctx context.Context) error {
+		revs = s.revocations.Batch()
+		return s.revokeAllSessions(ctx, revs, userID)
+	}); err != nil {
+		return fmt.Errorf("logging out everywhere: %w", err)
+	}
+	revs.Apply()
+	return nil
+}
+
+// ForgotPassword emails a single-use reset link if the email belongs to an
+// account that is not awaiting deletion. It returns nil for unknown emails so
+// callers cannot tell the two apart; the email is sent in the background for
+// the same reason.
+func (s *svc) ForgotPassword(ctx context.Context, input ForgotPasswordInput) error {
+	user, err := s.repo.GetUserByEmail(ctx, input.Email)
+	if err != nil || !user.DeletedAt.IsZero() {
+		return nil
+	}
+
+	return s.sendPasswordReset(ctx, user)
+}
+
+// sendPasswordReset stores a new reset token for user, invalidating older
+// ones, and emails the link in the background.
+func (s *svc) sendPasswordReset(ctx context.Context, user User) error {
+	var msg mailer.Message
+	err := s.tx.Atomic(ctx, func(ctx context.Context) error {
+		var err error
+		msg, err = s.issuePasswordReset(ctx, user)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("storing reset token: %w", err)
+	}
+
+	s.sendInBackground(ctx, msg)
+	return nil
+}
+
+// issuePasswordReset stores a new reset token for user, invalidating older
+// ones, and returns the email carrying its link. Call it inside a transaction
+// and send the email once it commits.
+func (s *svc) issuePasswordReset(ctx context.Context, user User) (mailer.Message, error) {
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		return mailer.Message{}, fmt.Errorf("generating reset token: %w", err)
+	}
+
+	// only the newest link stays valid
+	if err := s.repo.ConsumePasswordResetTokens(ctx, user.ID); err != nil {
+		return mailer.Message{}, err
+	}
+	if err := s.repo.CreatePasswordResetToken(ctx, CreatePasswordResetTokenParams{
+		ID:        cuid.New(),
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(s.cfg.PasswordResetTTL),
+	}); err != nil {
+		return mailer.Message{}, err
+	}
+
+	return passwordResetEmail(user, withToken(s.cfg.PasswordResetURL, raw), s.cfg.PasswordResetTTL), nil
+}
+
+// ResetPassword redeems a reset token, sets the new password and signs the
+// user out everywhere.
+func (s *svc) ResetPassword(ctx context.Context, input ResetPasswordInput) error {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("hashing password: %w", err)
+	}
+
+	var revs *RevocationBatch
+	err = s.tx.Atomic(ctx, func(ctx context.Context) error {
+		revs = s.revocations.Batch()
+		token, err := s.repo.GetPasswordResetTokenForUpdate(ctx, hashToken(input.Token))
+		if err != nil {
+			return err
+		}
+		if !token.UsedAt.IsZero() || time.Now().After(token.ExpiresAt) {
+			return ErrInvalidResetToken
+		}
+
+		if err := s.repo.ConsumePasswordResetTokens(ctx, token.UserID); err != nil {
+			return fmt.Errorf("consuming reset tokens: %w", err)
+		}
+		if err := s.repo.UpdatePassword(ctx, token.UserID, string(hashed)); err != nil {
+			return fmt.Errorf("updating password: %w", err)
+		}
+		return s.revokeAllSessions(ctx, revs, token.UserID)
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidResetToken) {
+			return err
+		}
+		return fmt.Errorf("resetting password: %w", err)
+	}
+	revs.Apply()
+
+	return nil
+}
+
+// ChangePassword sets a new password after checking the current one. Every
+// other session of the user is signed out; the caller's stays signed in.
+// Wrong current passwords count as failed logins; see LoginThrottle.
+func (s *svc) ChangePassword(ctx context.Context, input ChangePasswordInput) error {
+	user, err := s.repo.GetUserByID(ctx, input.UserID)
+	if err != nil {
+		return fmt.Errorf("loading user: %w", err)
+	}
+	if err := s.checkPassword(ctx, user, input.CurrentPassword, input.Client); err != nil {
+		return err
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("hashing password: %w", err)
+	}
+
+	var revs *RevocationBatch
+	err = s.tx.Atomic(ctx, func(ctx context.Context) error {
+		revs = s.revocations.Batch()
+		if err := s.repo.UpdatePassword(ctx, user.ID, string(hashed)); err != nil {
+			return fmt.Errorf("updating password: %w", err)
+		}
+		// a reset link sent before the change would otherwise undo it
+		if err := s.repo.ConsumePasswordResetTokens(ctx, user.ID); err != nil {
+			return fmt.Errorf("consuming reset tokens: %w", err)
+		}
+		return s.revokeOtherSessions(ctx, revs, user.ID, input.SessionID)
+	})
+	if err != nil {
+		return fmt.Errorf("changing password: %w", err)
+	}
+	revs.Apply()
+
+	s.sendInBackground(ctx, passwordChangedEmail(user))
+	return nil
+}
+
+// RequestEmailChange emails a confirmation link to the new address after
+// checking the user's password. The address is only changed by
+// ConfirmEmailChange, and only the newest link stays valid.
+func (s *svc) RequestEmailChange(ctx context.Context, input ChangeEmailInput) error {
+	if addr, err := mail.ParseAddress(input.NewEmail); err != nil || addr.Address != input.NewEmail {
+		return ErrInvalidEmail
+	}
+
+	user, err := s.repo.GetUserByID(ctx, input.UserID)
+	if err != nil {
+		return fmt.Errorf("loading user: %w", err)
+	}
+	if err := s.checkPassword(ctx, user, input.Password, input.Client); err != nil {
+		return err
+	}
+	if input.NewEmail == user.Email {
+		return ErrEmailUnchanged
+	}
+
+	if _, err := s.repo.GetUserByEmail(ctx, input.NewEmail); err == nil {
+		return ErrEmailTaken
+	} else if !errors.Is(err, ErrUserNotFound) {
+		return fmt.Errorf("loading user: %w", err)
+	}
+
+	sent, err := s.repo.CountEmailChangesSince(ctx, user.ID, time.Now().Add(-time.Hour))
+	if err != nil {
+		return fmt.Errorf("counting email changes: %w", err)
+	}
+	if sent >= maxEmailChangesPerHour {
+		return ErrEmailChangeThrottled
+	}
+
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("generating email change token: %w", err)
+	}
+
+	err = s.tx.Atomic(ctx, func(ctx context.Context) error {
+		if err := s.repo.ConsumeEmailChangeTokens(ctx, user.ID); err != nil {
+			return err
+		}
+		return s.repo.CreateEmailChangeToken(ctx, CreateEmailChangeTokenParams{
+			ID:        cuid.New(),
+			UserID:    user.ID,
+			NewEmail:  input.NewEmail,
+			TokenHash: hash,
+			ExpiresAt: time.Now().Add(s.cfg.EmailChangeTTL),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("storing email change token: %w", err)
+	}
+
+	msg := emailChangeEmail(user, input.NewEmail, withToken(s.cfg.EmailChangeURL, raw), s.cfg.EmailChangeTTL)
+	s.sendInBackground(ctx, msg)
+
+	return nil
+}
+
+// ConfirmEmailChange redeems a link sent by RequestEmailChange and switches
+// the account to the new, now verified, address. Links mailed to the old
+// address stop working, and the old address is told about the change.
+func (s *svc) ConfirmEmailChange(ctx context.Context, input ConfirmEmailChangeInput) error {
+	var user User
+	var newEmail string
+	err := s.tx.Atomic(ctx, func(ctx context.Context) error {
+		token, err := s.repo.GetEmailChangeTokenForUpdate(ctx, hashToken(input.Token))
+		if err != nil {
+			return err
+		}
+		if !token.UsedAt.IsZero() || time.Now().After(token.ExpiresAt) {
+			return ErrInvalidEmailChangeToken
+		}
+
+		user, err = s.repo.GetUserByID(ctx, token.UserID)
+		if err != nil {
+			return fmt.Errorf("loading user: %w", err)
+		}
+		newEmail = token.NewEmail
+
+		if err := s.repo.ConsumeEmailChangeTokens(ctx, user.ID); err != nil {
+			return fmt.Errorf("consuming email change tokens: %w", err)
+		}
+		if err := s.repo.UpdateEmail(ctx, user.ID, newEmail); err != nil {
+			return err
+		}
+		if err := s.repo.ConsumePasswordResetTokens(ctx, user.ID); err != nil {
+			return fmt.Errorf("consuming reset tokens: %w", err)
+		}
+		if err := s.repo.ConsumeMagicLinkTokens(ctx, user.ID); err != nil {
+			return fmt.Errorf("consuming login links: %w", err)
+		}
+		return s.repo.ConsumeEmailVerificationTokens(ctx, user.ID)
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidEmailChangeToken) || errors.Is(err, ErrEmailTaken) {
+			return err
+		}
+		return fmt.Errorf("changing email: %w", err)
+	}
+
+	s.sendInBackground(ctx, emailChangedEmail(user, newEmail))
+	return nil
+}
+
+// DeleteAccount deletes the caller's account after checking their password.
+// It is signed out everywhere and can no longer sign in, but stays restorable
+// by an admin until AccountDeletionGrace has passed; then the privacy worker
+// purges it with all its data. The request is recorded in the audit log.
+func (s *svc) DeleteAccount(ctx context.Context, input DeleteAccountInput) (DeleteAccountResponse, error) {
+	user, err := s.repo.GetUserByID(ctx, input.UserID)
+	if err != nil {
+		return DeleteAccountResponse{}, fmt.Errorf("loading user: %w", err)
+	}
+	if err := s.checkPassword(ctx, user, input.Password, input.Client); err != nil {
+		return DeleteAccountResponse{}, err
+	}
+
+	var (
+		deletedAt time.Time
+		revs      *RevocationBatch
+	)
+	err = s.tx.Atomic(ctx, func(ctx context.Context) error {
+		revs = s.revocations.Batch()
+		deletedAt, err = s.repo.SoftDeleteUser(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("marking account deleted: %w", err)
+		}
+		if err := s.repo.CreateAuditEntry(ctx, cuid.New(), user.ID, ActionDeleteAccount, user.ID); err != nil {
+			return fmt.Errorf("recording audit entry: %w", err)
+		}
+		return s.revokeAllSessions(ctx, revs, user.ID)
+	})
+	if err != nil {
+		return DeleteAccountResponse{}, fmt.Errorf("deleting account: %w", err)
+	}
+	revs.Apply()
+
+	resp := DeleteAccountResponse{
+		DeletedAt:  deletedAt,
+		PurgeAfter: deletedAt.Add(s.cfg.AccountDeletionGrace),
+	}
+	s.sendInBackground(ctx, accountDeletedEmail(user, resp.PurgeAfter))
+	return resp, nil
+}
+
+// checkPassword verifies a signed-in user's password before a sensitive
+// change, with the same lockout as Login.
+func (s *svc) checkPassword(ctx context.Context, user User, password string, client ClientInfo) error {
+	if err := s.throttle.Check(ctx, user.Email, client.IP); err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		if err := s.throttle.Failed(ctx, user.Email, client.IP); err != nil {
+			slog.ErrorContext(ctx, "recording login failure", "error", err)
+		}
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+// RequestMagicLink emails a single-use login link bound to the requesting
+// browser, if the email belongs to an unlocked account. Like ForgotPassword it
+// returns nil for unknown emails, and only the newest link stays valid.
+func (s *svc) RequestMagicLink(ctx context.Context, input MagicLinkInput) error {
+	user, err := s.repo.GetUserByEmail(ctx, input.Email)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil
+		}
+		return fmt.Errorf("loading user: %w", err)
+	}
+	if checkAccountUsable(user) != nil {
+		return nil
+	}
+
+	sent, err := s.repo.CountMagicLinksSince(ctx, user.ID, time.Now().Add(-time.Hour))
+	if err != nil {
+		return fmt.Errorf("counting login links: %w", err)
+	}
+	if sent >= maxMagicLinksPerHour {
+		return nil
+	}
+
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("generating login link: %w", err)
+	}
+
+	err = s.tx.Atomic(ctx, func(ctx context.Context) error {
+		if err := s.repo.ConsumeMagicLinkTokens(ctx, user.ID); err != nil {
+			return err
+		}
+		return s.repo.CreateMagicLinkToken(ctx, CreateMagicLinkTokenParams{
+			ID:          cuid.New(),
+			UserID:      user.ID,
+			TokenHash:   hash,
+			BrowserHash: hashToken(input.BrowserNonce),
+			ExpiresAt:   time.Now().Add(s.cfg.MagicLinkTTL),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("storing login link: %w", err)
+	}
+
+	msg := magicLinkEmail(user, withToken(s.cfg.MagicLinkURL, raw), s.cfg.MagicLinkTTL)
+	s.sendInBackground(ctx, msg)
+
+	return nil
+}
+
+// ConsumeMagicLink redeems a login link opened in the browser that requested
+// it and logs the user in like Login: tokens, or an MFA challenge when TOTP is
+// enabled. Locked accounts and pending forced resets are refused as in Login.
+// Following the link proves the email address, so it is marked verified. A
+// link opened in another browser is refused but not consumed.
+func (s *svc) ConsumeMagicLink(ctx context.Context, input ConsumeMagicLinkInput) (LoginResult, error) {
+	result, err := s.consumeMagicLink(ctx, input)
+	s.recordLogin(LoginMethodMagicLink, result.MFA != nil, err)
+	return result, err
+}
+
+// consumeMagicLink implements ConsumeMagicLink; ConsumeMagicLink records the outcome.
+func (s *svc) consumeMagicLink(ctx context.Context, input ConsumeMagicLinkInput) (LoginResult, error) {
+	var user User
+	err := s.tx.Atomic(ctx, func(ctx context.Context) error {
+		token, err := s.repo.GetMagicLinkTokenForUpdate(ctx, hashToken(input.Token))
+		if err != nil {
+			return err
+		}
+		if !token.UsedAt.IsZero() || time.Now().After(token.ExpiresAt) {
+			return ErrInvalidMagicLink
+		}
+		if input.BrowserNonce == "" || subtle.ConstantTimeCompare([]byte(hashToken(input.BrowserNonce)), []byte(token.BrowserHash)) != 1 {
+			return ErrMagicLinkWrongBrowser
+		}
+
+		if err := s.repo.ConsumeMagicLinkTokens(ctx, token.UserID); err != nil {
+			return fmt.Errorf("consuming login links: %w", err)
+		}
+
+		user, err = s.repo.GetUserByID(ctx, token.UserID)
+		if err != nil {
+			return fmt.Errorf("loading user: %w", err)
+		}
+		if user.EmailVerifiedAt.IsZero() {
+			if err := s.repo.MarkEmailVerified(ctx, user.ID); err != nil {
+				return fmt.Errorf("marking email verified: %w", err)
+			}
+			user.EmailVerifiedAt = time.Now()
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidMagicLink) || errors.Is(err, ErrMagicLinkWrongBrowser) {
+			return LoginResult{}, err
+		}
+		return LoginResult{}, fmt.Errorf("consuming login link: %w", err)
+	}
+
+	if err := checkAccountUsable(user); err != nil {
+		return LoginResult{}, err
+	}
+	if user.PasswordResetRequired {
+		return LoginResult{}, ErrPasswordResetRequired
+	}
+	return s.signIn(ctx, user, input.Client)
+}
+
+// VerifyEmail redeems a verification token and marks the user's email verified.
+func (s *svc) VerifyEmail(ctx context.Context, input VerifyEmailInput) error {
+	err := s.tx.Atomic(ctx, func(ctx context.Context) error {
+		token, err := s.repo.GetEmailVerificationTokenForUpdate(ctx, hashToken(input.Token))
+		if err != nil {
+			return err
+		}
+		if !token.UsedAt.IsZero() || time.Now().After(token.ExpiresAt) {
+			return ErrInvalidVerificationToken
+		}
+
+		if err := s.repo.ConsumeEmailVerificationTokens(ctx, token.UserID); err != nil {
+			return fmt.Errorf("consuming verification tokens: %w", err)
+		}
+		return s.repo.MarkEmailVerified(ctx, token.UserID)
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidVerificationToken) {
+			return err
+		}
+		return fmt.Errorf("verifying email: %w", err)
+	}
+
+	return nil
+}
+
+// ResendVerification emails a fresh verification link, at most once per
+// VerificationResendCooldown and maxVerificationEmailsPerHour times an hour.
+func (s *svc) ResendVerification(ctx context.Context, userID string) error {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("loading user: %w", err)
+	}
+	if !user.EmailVerifiedAt.IsZero() {
+		return ErrEmailAlreadyVerified
+	}
+
+	now := time.Now()
+	stats, err := s.repo.GetVerificationSendStats(ctx, userID, now.Add(-time.Hour))
+	if err != nil {
+		return fmt.Errorf("loading send stats: %w", err)
+	}
+	if stats.Sent >= maxVerificationEmailsPerHour || now.Sub(stats.LastSentAt) < s.cfg.VerificationResendCooldown {
+		return ErrVerificationThrottled
+	}
+
+	return s.sendVerification(ctx, user)
+}
+
+// IsEmailVerified reports whether the user has confirmed their email address.
+func (s *svc) IsEmailVerified(ctx context.Context, userID string) (bool, error) {
+	return s.repo.IsEmailVerified(ctx, userID)
+}
+
+// sendVerification replaces any outstanding verification token of user with
+// a new one and emails the link in the background.
+func (s *svc) sendVerification(ctx context.Context, user User) error {
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("generating verification token: %w", err)
+	}
+
+	err = s.tx.Atomic(ctx, func(ctx context.Context) error {
+		if err := s.repo.ConsumeEmailVerificationTokens(ctx, user.ID); err != nil {
+			return err
+		}
+		return s.repo.CreateEmailVerificationToken(ctx, CreateEmailVerificationTokenParams{
+			ID:        cuid.New(),
+			UserID:    user.ID,
+			TokenHash: hash,
+			ExpiresAt: time.Now().Add(s.cfg.EmailVerificationTTL),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("storing verification token: %w", err)
+	}
+
+	msg := verificationEmail(user, withToken(s.cfg.EmailVerificationURL, raw), s.cfg.EmailVerificationTTL)
+	s.sendInBackground(ctx, msg)
+
+	return nil
+}
+
+// SetupTOTP starts TOTP enrollment with a new secret. Calling it again before
+// ConfirmTOTP replaces the pending secret.
+func (s *svc) SetupTOTP(ctx context.Context, userID string) (TOTPSetupResponse, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return TOTPSetupResponse{}, fmt.Errorf("loading user: %w", err)
+	}
+
+	existing, err := s.repo.GetTOTP(ctx, userID)
+	if err != nil && !errors.Is(err, ErrTOTPNotEnrolled) {
+		return TOTPSetupResponse{}, fmt.Errorf("loading two-factor settings: %w", err)
+	}
+	if err == nil && !existing.ConfirmedAt.IsZero() {
+		return TOTPSetupResponse{}, ErrTOTPAlreadyEnabled
+	}
+
+	key, err := newTOTPKey(s.cfg.TOTPIssuer, user.Email)
+	if err != nil {
+		return TOTPSetupResponse{}, fmt.Errorf("generating TOTP secret: %w", err)
+	}
+
+	sealed, err := s.cfg.Secrets.seal([]byte(key.Secret()), []byte(userID))
+	if err != nil {
+		return TOTPSetupResponse{}, fmt.Errorf("encrypting TOTP secret: %w", err)
+	}
+
+	if err := s.repo.UpsertPendingTOTP(ctx, userID, sealed); err != nil {
+		return TOTPSetupResponse{}, fmt.Errorf("storing TOTP secret: %w", err)
+	}
+
+	qr, err := qrCodePNG(key)
+	if err != nil {
+		return TOTPSetupResponse{}, err
+	}
+
+	return TOTPSetupResponse{
+		Secret:     key.Secret(),
+		OTPAuthURL: key.URL(),
+		QRCodePNG:  qr,
+	}, nil
+}
+
+// ConfirmTOTP enables 2FA once the user proves their authenticator produces
+// valid codes, and returns a fresh set of recovery codes.
+func (s *svc) ConfirmTOTP(ctx context.Context, input ConfirmTOTPInput) (RecoveryCodesResponse, error) {
+	var codes []string
+
+	err := s.tx.Atomic(ctx, func(ctx context.Context) error {
+		enrollment, err := s.repo.GetTOTPForUpdate(ctx, input.UserID)
+		if err != nil {
+			return err
+		}
+		if !enrollment.ConfirmedAt.IsZero() {
+			return ErrTOTPAlreadyEnabled
+		}
+
+		step, ok, err := s.checkTOTP(enrollment, input.Code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidTOTPCode
+		}
+
+		if err := s.repo.ConfirmTOTP(ctx, input.UserID, step); err != nil {
+			return fmt.Errorf("confirming TOTP: %w", err)
+		}
+
+		var hashes []string
+		codes, hashes, err = newRecoveryCodes()
+		if err != nil {
+			return err
+		}
+		if err := s.repo.DeleteRecoveryCodes(ctx, input.UserID); err != nil {
+			return fmt.Errorf("deleting recovery codes: %w", err)
+		}
+		for _, hash := range hashes {
+			if err := s.repo.CreateRecoveryCode(ctx, cuid.New(), input.UserID, hash); err != nil {
+				return fmt.Errorf("storing recovery code: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrTOTPNotEnrolled) || errors.Is(err, ErrTOTPAlreadyEnabled) || errors.Is(err, ErrInvalidTOTPCode) {
+			return RecoveryCodesResponse{}, err
+		}
+		return RecoveryCodesResponse{}, fmt.Errorf("confirming TOTP: %w", err)
+	}
+
+	return RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// VerifyMFA exchanges an mfa_pending token plus a TOTP or recovery code for
+// tokens. Wrong codes count against the challenge; after maxMFAAttempts the
+// user has to log in again.
+func (s *svc) VerifyMFA(ctx context.Context, input VerifyMFAInput) (AuthResponse, error) {
+	result, err := s.verifyMFA(ctx, input)
+	s.recordLogin(LoginMethodMFA, false, err)
+	return result, err
+}
+
+// verifyMFA implements VerifyMFA; VerifyMFA records the outcome.
+func (s *svc) verifyMFA(ctx context.Context, input VerifyMFAInput) (AuthResponse, error) {
+	var (
+		resp   AuthResponse
+		failed bool
+	)
+
+	err := s.tx.Atomic(ctx, func(ctx context.Context) error {
+		failed = false
+
+		challenge, err := s.repo.GetMFAChallengeForUpdate(ctx, hashToken(input.MFAToken))
+		if err != nil {
+			return err
+		}
+		if !challenge.UsedAt.IsZero() || time.Now().After(challenge.ExpiresAt) || challenge.Attempts >= maxMFAAttempts {
+			return ErrInvalidMFAChallenge
+		}
+
+		ok, err := s.checkSecondFactor(ctx, challenge.UserID, input)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if err := s.repo.IncrementMFAChallengeAttempts(ctx, challenge.ID); err != nil {
+				return fmt.Errorf("counting mfa attempt: %w", err)
+			}
+			// commit the attempt; the error is reported after the transaction
+			failed = true
+			return nil
+		}
+
+		if err := s.repo.MarkMFAChallengeUsed(ctx, challenge.ID); err != nil {
+			return fmt.Errorf("consuming mfa challenge: %w", err)
+		}
+
+		user, err := s.repo.GetUserByID(ctx, challenge.UserID)
+		if err != nil {
+			return fmt.Errorf("loading user: %w", err)
+		}
+
+		resp, err = s.startSession(ctx, user, input.Client)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidMFAChallenge) || errors.Is(err, ErrAccountLocked) {
+			return AuthResponse{}, err
+		}
+		return AuthResponse{}, fmt.Errorf("verifying mfa: %w", err)
+	}
+	if failed {
+		return AuthResponse{}, ErrInvalidTOTPCode
+	}
+
+	return resp, nil
+}
+
+// newMFAChallenge stores a challenge for userID and returns its token.
+func (s *svc) newMFAChallenge(ctx context.Context, userID string) (MFAChallengeResponse, error) {
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		return MFAChallengeResponse{}, fmt.Errorf("generating mfa token: %w", err)
+	}
+
+	if err := s.repo.CreateMFAChallenge(ctx, CreateMFAChallengeParams{
+		ID:        cuid.New(),
+		UserID:    userID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(s.cfg.MFAChallengeTTL),
+	}); err != nil {
+		return MFAChallengeResponse{}, fmt.Errorf("storing mfa challenge: %w", err)
+	}
+
+	return MFAChallengeResponse{
+		Status:    "mfa_pending",
+		MFAToken:  raw,
+		ExpiresIn: int64(s.cfg.MFAChallengeTTL / time.Second),
+	}, nil
+}
+
+// checkSecondFactor validates a recovery code (consuming it) or a TOTP code
+// (recording its step so it cannot be replayed). Call it inside a transaction.
+func (s *svc) checkSecondFactor(ctx context.Context, userID string, input VerifyMFAInput) (bool, error) {
+	if input.RecoveryCode != "" {
+		ok, err := s.repo.UseRecoveryCode(ctx, userID, hashRecoveryCode(input.RecoveryCode))
+		if err != nil {
+			return false, fmt.Errorf("using recovery code: %w", err)
+		}
+		return ok, nil
+	}
+
+	enrollment, err := s.repo.GetTOTPForUpdate(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("loading two-factor settings: %w", err)
+	}
+
+	step, ok, err := s.checkTOTP(enrollment, input.Code)
+	if err != nil || !ok {
+		return false, err
+	}
+	if step <= enrollment.LastUsedStep {
+		return false, nil // replayed code
+	}
+
+	if err := s.repo.UpdateTOTPLastUsedStep(ctx, userID, step); err != nil {
+		return false, fmt.Errorf("recording TOTP step: %w", err)
+	}
+	return true, nil
+}
+
+// checkTOTP decrypts the enrollment's secret and matches code against it.
+func (s *svc) checkTOTP(enrollment TOTPEnrollment, code string) (int64, bool, error) {
+	secret, err := s.cfg.Secrets.open(enrollment.SecretEncrypted, []byte(enrollment.UserID))
+	if err != nil {
+		return 0, false, fmt.Errorf("decrypting TOTP secret: %w", err)
+	}
+
+	step, ok := matchTOTP(string(secret), code, time.Now())
+	return step, ok, nil
+}
+
+// CreateAPIKey mints a key with the given scopes. The raw key is returned
+// once; only its hash is stored.
+func (s *svc) CreateAPIKey(ctx context.Context, input CreateAPIKeyInput) (CreatedAPIKeyResponse, error) {
+	for _, scope := range input.Scopes {
+		if !slices.Contains(validScopes, scope) {
+			return CreatedAPIKeyResponse{}, fmt.Errorf("%w: %q", ErrInvalidScope, scope)
+		}
+	}
+
+	raw, prefix, hash, err := newAPIKey()
+	if err != nil {
+		return CreatedAPIKeyResponse{}, err
+	}
+
+	key, err := s.repo.CreateAPIKey(ctx, CreateAPIKeyParams{
+		ID:        cuid.New(),
+		UserID:    input.UserID,
+		Name:      input.Name,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(input.Scopes))),
+		ExpiresAt: input.ExpiresAt,
+	})
+	if err != nil {
+		return CreatedAPIKeyResponse{}, fmt.Errorf("creating api key: %w", err)
+	}
+
+	return CreatedAPIKeyResponse{APIKeyPayload: toAPIKeyPayload(key), Key: raw}, nil
+}
+
+// ListAPIKeys returns the user's API keys without their secrets.
+func (s *svc) ListAPIKeys(ctx context.Context, userID string) (APIKeyListResponse, error) {
+	keys, err := s.repo.ListAPIKeys(ctx, userID)
+	if err != nil {
+		return APIKeyListResponse{}, fmt.Errorf("listing api keys: %w", err)
+	}
+
+	resp := APIKeyListResponse{APIKeys: make([]APIKeyPayload, len(keys))}
+	for i, key := range keys {
+		resp.APIKeys[i] = toAPIKeyPayload(key)
+	}
+	return resp, nil
+}
+
+// DeleteAPIKey revokes one of the user's API keys immediately.
+func (s *svc) DeleteAPIKey(ctx context.Context, userID, id string) error {
+	return s.repo.DeleteAPIKey(ctx, id, userID)
+}
+
+// AuthenticateAPIKey resolves a raw key presented to RequireAuth and records
+// its use.
+func (s *svc) AuthenticateAPIKey(ctx context.Context, raw string) (APIKey, error) {
+	key, err := s.repo.GetAPIKeyByHash(ctx, hashToken(raw))
+	if err != nil {
+		return APIKey{}, err
+	}
+	if key.expired(time.Now()) {
+		return APIKey{}, ErrInvalidAPIKey
+	}
+
+	// last_used_at is informational; never fail a request over it
+	if err := s.repo.TouchAPIKey(ctx, key.ID); err != nil {
+		slog.ErrorContext(ctx, "recording api key use", "api_key_id", key.ID, "error", err)
+	}
+
+	return key, nil
+}
+
+// StartOIDC begins an authorization code flow with PKCE at provider. The
+// nonce and code verifier stay in the database; only the state and the S256
+// challenge travel through the browser.
+func (s *svc) StartOIDC(ctx context.Context, provider string) (OIDCAuthRequest, error) {
+	op, err := s.cfg.OIDC.get(ctx, provider)
+	if err != nil {
+		return OIDCAuthRequest{}, err
+	}
+
+	state, stateHash, err := newOpaqueToken()
+	if err != nil {
+		return OIDCAuthRequest{}, fmt.Errorf("generating state: %w", err)
+	}
+	url, nonce, verifier, err := op.authRequest(state)
+	if err != nil {
+		return OIDCAuthRequest{}, err
+	}
+
+	// abandoned logins are swept whenever a new one starts
+	if _, err := s.repo.DeleteExpiredOIDCLoginStates(ctx); err != nil {
+		slog.ErrorContext(ctx, "deleting expired oidc login states", "error", err)
+	}
+
+	if err := s.repo.CreateOIDCLoginState(ctx, CreateOIDCLoginStateParams{
+		StateHash:    stateHash,
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(s.cfg.OIDCStateTTL),
+	}); err != nil {
+		return OIDCAuthRequest{}, fmt.Errorf("storing oidc login state: %w", err)
+	}
+
+	return OIDCAuthRequest{URL: url, State: state, ExpiresIn: s.cfg.OIDCStateTTL}, nil
+}
+
+// CompleteOIDC finishes a flow started by StartOIDC. A known provider subject
+// signs in as its linked user. An unknown one is linked to the account with
+// the same email, or gets a new account, but only when the provider says the
+// email is verified. Locked accounts are refused and TOTP still applies.
+func (s *svc) CompleteOIDC(ctx context.Context, input OIDCCallbackInput) (LoginResult, error) {
+	result, err := s.completeOIDC(ctx, input)
+	s.recordLogin(LoginMethodOIDC, result.MFA != nil, err)
+	return result, err
+}
+
+// completeOIDC implements CompleteOIDC; CompleteOIDC records the outcome.
+func (s *svc) completeOIDC(ctx context.Context, input OIDCCallbackInput) (LoginResult, error) {
+	login, err := s.repo.ConsumeOIDCLoginState(ctx, hashToken(input.State))
+	if err != nil {
+		if errors.Is(err, ErrInvalidOIDCState) {
+			return LoginResult{}, err
+		}
+		return LoginResult{}, fmt.Errorf("loading oidc login state: %w", err)
+	}
+	if login.Provider != input.Provider || time.Now().After(login.ExpiresAt) {
+		return LoginResult{}, ErrInvalidOIDCState
+	}
+
+	op, err := s.cfg.OIDC.get(ctx, input.Provider)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	claims, err := op.exchange(ctx, input.Code, login)
+	if err != nil {
+		slog.WarnContext(ctx, "oidc login failed", "provider", input.Provider, "error", err)
+		return LoginResult{}, ErrOIDCLoginFailed
+	}
+
+	var (
+		user User
+		revs *RevocationBatch
+	)
+	err = s.tx.Atomic(ctx, func(ctx context.Context) error {
+		revs = s.revocations.Batch()
+		var err error
+		user, err = s.oidcUser(ctx, revs, input.Provider, claims)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrOIDCEmailNotVerified) || errors.Is(err, ErrEmailTaken) {
+			return LoginResult{}, err
+		}
+		return LoginResult{}, fmt.Errorf("resolving oidc user: %w", err)
+	}
+	revs.Apply()
+
+	if err := checkAccountUsable(user); err != nil {
+		return LoginResult{}, err
+	}
+	return s.signIn(ctx, user, input.Client)
+}
+
+// oidcUser returns the user linked to the provider subject, linking or
+// creating one on first sign-in. Call it inside a transaction and apply revs
+// once it commits.
+func (s *svc) oidcUser(ctx context.Context, revs *RevocationBatch, provider string, claims oidcClaims) (User, error) {
+	user, err := s.repo.GetUserByIdentity(ctx, provider, claims.Subject)
+	if err == nil {
+		return user, s.repo.TouchUserIdentity(ctx, provider, claims.Subject, claims.Email)
+	}
+	if !errors.Is(err, ErrUserNotFound) {
+		return User{}, fmt.Errorf("loading linked user: %w", err)
+	}
+
+	// linking by email hands over an existing account, so only trust addresses the provider verified
+	if claims.Email == "" || !claims.EmailVerified {
+		return User{}, ErrOIDCEmailNotVerified
+	}
+
+	user, err = s.repo.GetUserByEmail(ctx, claims.Email)
+	switch {
+	case errors.Is(err, ErrUserNotFound):
+		if user, err = s.createOIDCUser(ctx, claims); err != nil {
+			return User{}, err
+		}
+	case err != nil:
+		return User{}, fmt.Errorf("loading user by email: %w", err)
+	case user.EmailVerifiedAt.IsZero():
+		// whoever registered this unverified address may not own it: the
+		// provider has just proven who does, so shut the password and any
+		// sessions out before handing the account over
+		if err := s.resetToUnusablePassword(ctx, revs, user.ID); err != nil {
+			return User{}, err
+		}
+	}
+
+	if user.EmailVerifiedAt.IsZero() {
+		if err := s.repo.MarkEmailVerified(ctx, user.ID); err != nil {
+			return User{}, fmt.Errorf("marking email verified: %w", err)
+		}
+		user.EmailVerifiedAt = time.Now()
+	}
+
+	if err := s.repo.CreateUserIdentity(ctx, CreateUserIdentityParams{
+		Provider: provider,
+		Subject:  claims.Subject,
+		UserID:   user.ID,
+		Email:    claims.Email,
+	}); err != nil {
+		return User{}, fmt.Errorf("linking identity: %w", err)
+	}
+
+	return user, nil
+}
+
+// createOIDCUser signs up a user from ID token claims. The account gets an
+// unusable random password; the user can set one through ForgotPassword.
+func (s *svc) createOIDCUser(ctx context.Context, claims oidcClaims) (User, error) {
+	hashed, err := unusablePasswordHash()
+	if err != nil {
+		return User{}, err
+	}
+
+	name := claims.Name
+	if name == "" {
+		name, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	user, err := s.repo.CreateUser(ctx, CreateUserParams{
+		ID:             cuid.New(),
+		Name:           name,
+		Email:          claims.Email,
+		Password:       hashed,
+		ProfilePicture: claims.Picture,
+	})
+	if err != nil {
+		if errors.Is(err, ErrEmailTaken) {
+			return User{}, err
+		}
+		return User{}, fmt.Errorf("creating user: %w", err)
+	}
+	return user, nil
+}
+
+// resetToUnusablePassword replaces the user's password with a random one and
+// revokes their sessions. Call it inside a transaction and apply revs once it
+// commits.
+func (s *svc) resetToUnusablePassword(ctx context.Context, revs *RevocationBatch, userID string) error {
+	hashed, err := unusablePasswordHash()
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdatePassword(ctx, userID, hashed); err != nil {
+		return fmt.Errorf("updating password: %w", err)
+	}
+	return s.revokeAllSessions(ctx, revs, userID)
+}
+
+// LockAccount locks the user out: sessions are revoked, and login, refresh
+// and API keys are refused until UnlockAccount.
+func (s *svc) LockAccount(ctx context.Context, userID string, audit AuditFunc) error {
+	var revs *RevocationBatch
+	err := s.tx.Atomic(ctx, func(ctx context.Context) error {
+		revs = s.revocations.Batch()
+		if err := s.repo.LockUser(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.revokeAllSessions(ctx, revs, userID); err != nil {
+			return err
+		}
+		return audit(ctx)
+	})
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return err
+		}
+		return fmt.Errorf("locking account: %w", err)
+	}
+	revs.Apply()
+	return nil
+}
+
+// UnlockAccount lets a locked user sign in again, lifting any brute-force
+// lockout on their email too.
+func (s *svc) UnlockAccount(ctx context.Context, userID string, audit AuditFunc) error {
+	err := s.tx.Atomic(ctx, func(ctx context.Context) error {
+		if err := s.repo.UnlockUser(ctx, userID); err != nil {
+			return err
+		}
+
+		user, err := s.repo.GetUserByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if err := s.throttle.Unlock(ctx, user.Email); err != nil {
+			return err
+		}
+		return audit(ctx)
+	})
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return err
+		}
+		return fmt.Errorf("unlocking account: %w", err)
+	}
+	return nil
+}
+
+// RequirePasswordReset signs the user out everywhere, blocks login until the
+// password is changed and emails them a reset link.
+func (s *svc) RequirePasswordReset(ctx context.Context, userID string, audit AuditFunc) error {
+	var (
+		msg  mailer.Message
+		revs *RevocationBatch
+	)
+	err := s.tx.Atomic(ctx, func(ctx context.Context) error {
+		revs = s.revocations.Batch()
+		if err := s.repo.RequirePasswordReset(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.revokeAllSessions(ctx, revs, userID); err != nil {
+			return err
+		}
+
+		user, err := s.repo.GetUserByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if msg, err = s.issuePasswordReset(ctx, user); err != nil {
+			return fmt.Errorf("storing reset token: %w", err)
+		}
+		return audit(ctx)
+	})
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return err
+		}
+		return fmt.Errorf("requiring password reset: %w", err)
+	}
+	revs.Apply()
+
+	s.sendInBackground(ctx, msg)
+	return nil
+}
+
+// RestoreAccount cancels a pending account deletion. The user signs in again
+// as usual; their old sessions stay revoked.
+func (s *svc) RestoreAccount(ctx context.Context, userID string, audit AuditFunc) error {
+	err := s.tx.Atomic(ctx, func(ctx context.Context) error {
+		if err := s.repo.RestoreUser(ctx, userID); err != nil {
+			return err
+		}
+		return audit(ctx)
+	})
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) || errors.Is(err, ErrAccountPurging) {
+			return err
+		}
+		return fmt.Errorf("restoring account: %w", err)
+	}
+	return nil
+}
+
+// revokeOtherSessions signs out every session of the user except keepID.
+// Call it inside a transaction and apply revs once it commits.
+func (s *svc) revokeOtherSessions(ctx context.Context, revs *RevocationBatch, userID, keepID string) error {
+	sessions, err := s.repo.ListSessions(ctx, userID, time.Time{})
+	if err != nil {
+		return fmt.Errorf("listing sessions: %w", err)
+	}
+	for _, session := range sessions {
+		if session.ID == keepID {
+			continue
+		}
+		if err := s.repo.RevokeRefreshTokenFamily(ctx, session.ID); err != nil {
+			return fmt.Errorf("revoking token family: %w", err)
+		}
+		if err := revs.RevokeSession(ctx, session.ID); err != nil {
+			return fmt.Errorf("revoking session: %w", err)
+		}
+	}
+	return nil
+}
+
+// revokeAllSessions revokes every refresh token of the user and bumps their
+// token version. Call it inside a transaction and apply revs once it commits.
+func (s *svc) revokeAllSessions(ctx context.Context, revs *RevocationBatch, userID string) error {
+	if err := s.repo.RevokeUserRefreshTokens(ctx, userID); err != nil {
+		return fmt.Errorf("revoking refresh tokens: %w", err)
+	}
+	if err := s.repo.RevokeUserSessions(ctx, userID); err != nil {
+		return fmt.Errorf("revoking sessions: %w", err)
+	}
+	return revs.BumpVersion(ctx, userID)
+}
+
+// sendInBackground sends msg once the request has returned, through
+// cfg.Tasks when set so shutdown waits for it.
+func (s *svc) sendInBackground(ctx context.Context, msg mailer.Message) {
+	ctx = context.WithoutCancel(ctx)
+	if s.cfg.Tasks == nil {
+		go s.send(ctx, msg)
+		return
+	}
+	s.cfg.Tasks.Do(func() { s.send(ctx, msg) })
+}
+
+// send delivers msg with a bounded timeout, logging failures. It runs in the
+// background, so there is nobody to return an error to.
+func (s *svc) send(ctx context.Context, msg mailer.Message) {
+	ctx, cancel := context.WithTimeout(ctx, mailTimeout)
+	defer cancel()
+
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		slog.ErrorContext(ctx, "sending email", "subject", msg.Subject, "error", err)
+	}
+}
+
+// startSession records a new session for user on client and issues its first
+// token pair.
+func (s *svc) startSession(ctx context.Context, user User, client ClientInfo) (AuthResponse, error) {
+	var resp AuthResponse
+	err := s.tx.Atomic(ctx, func(ctx context.Context) error {
+		sessionID := cuid.New()
+		if err := s.repo.CreateSession(ctx, CreateSessionParams{
+			ID:         sessionID,
+			UserID:     user.ID,
+			UserAgent:  client.UserAgent,
+			IP:         client.IP,
+			DeviceName: deviceName(client.UserAgent),
+		}); err != nil {
+			return fmt.Errorf("storing session: %w", err)
+		}
+
+		var err error
+		resp, err = s.issueTokens(ctx, user, sessionID)
+		return err
+	})
+	return resp, err
+}
+
+// issueTokens signs a new access token and stores a new refresh token in familyID.
+// It refuses locked accounts, which covers refresh and MFA completion too.
+func (s *svc) issueTokens(ctx context.Context, user User, familyID string) (AuthResponse, error) {
+	if err := checkAccountUsable(user); err != nil {
+		return AuthResponse{}, err
+	}
+
+	accessToken, err := generateToken(user, familyID, s.cfg.Keys, s.cfg.AccessTokenTTL)
+	if err != nil {
+		return AuthResponse{}, fmt.Errorf("generating token: %w", err)
+	}
+
+	refreshToken, refreshHash, err := newOpaqueToken()
+	if err != nil {
+		return AuthResponse{}, fmt.Errorf("generating refresh token: %w", err)
+	}
+
+	if err := s.repo.CreateRefreshToken(ctx, CreateRefreshTokenParams{
+		ID:        cuid.New(),
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: refreshHash,
+		ExpiresAt: time.Now().Add(s.cfg.RefreshTokenTTL),
+	}); err != nil {
+		return AuthResponse{}, fmt.Errorf("storing refresh token: %w", err)
+	}
+
+	return AuthResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.cfg.AccessTokenTTL / time.Second),
+		User: UserPayload{
+			ID:             user.ID,
+			Name:           user.Name,
+			Email:          user.Email,
+			ProfilePicture: user.ProfilePicture,
+			EmailVerified:  !user.EmailVerifiedAt.IsZero(),
+			Role:           user.Role,
+			CreatedAt:      user.CreatedAt,
+		},
+	}, nil
+}
+
+// unusablePasswordHash hashes a random secret nobody knows, for accounts that
+// sign in through an identity provider.
+func unusablePasswordHash() (string, error) {
+	raw, _, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(raw), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("hashing password: %w", err)
+	}
+	return string(hashed), nil
+}
+
+// checkAccountUsable returns ErrAccountDeleted for accounts awaiting deletion
+// and ErrAccountLocked for locked ones.
+func checkAccountUsable(user User) error {
+	if !user.DeletedAt.IsZero() {
+		return ErrAccountDeleted
+	}
+	if !user.LockedAt.IsZero() {
+		return ErrAccountLocked
+	}
+	return nil
 }
@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// loginFailureWindow is how long a failed login counts against an email or
+// IP. It is longer than any lockout so backoff keeps growing across lockouts.
+const loginFailureWindow = 24 * time.Hour
+
+// LoginThrottleConfig sets when failed logins lock an email or IP out.
+type LoginThrottleConfig struct {
+	MaxFailures   int           // failures per email before the first lockout
+	MaxIPFailures int           // failures per client IP before the first lockout
+	BaseLockout   time.Duration // first lockout; doubles with every further failure
+	MaxLockout    time.Duration // cap on a single lockout
+}
+
+// LoginThrottle counts failed logins per email and per client IP in Postgres,
+// so every replica sees the same counters, and locks a key out with
+// exponential backoff once it passes its limit. Emails are tracked whether or
+// not an account exists, so lockouts do not reveal registered addresses.
+type LoginThrottle struct {
+	repo Repository
+	cfg  LoginThrottleConfig
+}
+
+// NewLoginThrottle constructs a LoginThrottle backed by repo.
+func NewLoginThrottle(repo Repository, cfg LoginThrottleConfig) *LoginThrottle {
+	return &LoginThrottle{repo: repo, cfg: cfg}
+}
+
+// LoginLockedError is returned by Login while the email or client IP is
+// locked out. It matches ErrTooManyLoginAttempts with errors.Is.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("%s, retry in %s", ErrTooManyLoginAttempts, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginLockedError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
+
+// Check returns a *LoginLockedError if email or ip is locked out. It runs
+// before the password is checked, so locked-out guesses cost no bcrypt work.
+func (t *LoginThrottle) Check(ctx context.Context, email, ip string) error {
+	until, err := t.repo.GetLoginLockedUntil(ctx, t.keys(email, ip))
+	if err != nil {
+		return fmt.Errorf("checking login lockout: %w", err)
+	}
+	if wait := time.Until(until); wait > 0 {
+		return &LoginLockedError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// Failed records a failed login for email and ip and locks out whichever
+// has passed its limit.
+func (t *LoginThrottle) Failed(ctx context.Context, email, ip string) error {
+	limits := map[string]int{emailKey(email): t.cfg.MaxFailures}
+	if ip != "" {
+		limits[ipKey(ip)] = t.cfg.MaxIPFailures
+	}
+
+	for key, limit := range limits {
+		failures, err := t.repo.RecordLoginFailure(ctx, key, time.Now().Add(-loginFailureWindow))
+		if err != nil {
+			return fmt.Errorf("recording login failure: %w", err)
+		}
+		if lockout := t.lockout(failures, limit); lockout > 0 {
+			if err := t.repo.LockLoginKey(ctx, key, time.Now().Add(lockout)); err != nil {
+				return fmt.Errorf("locking out %s: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Succeeded clears the email's failures. IP counters only decay, so an
+// attacker cannot reset them by logging into an account of their own.
+func (t *LoginThrottle) Succeeded(ctx context.Context, email string) error {
+	return t.repo.ClearLoginFailures(ctx, emailKey(email))
+}
+
+// Unlock clears an email's lockout early, e.g. when an admin unlocks the account.
+func (t *LoginThrottle) Unlock(ctx context.Context, email string) error {
+	return t.repo.ClearLoginFailures(ctx, emailKey(email))
+}
+
+// Prune deletes counters that have aged out of the failure window.
+func (t *LoginThrottle) Prune(ctx context.Context) error {
+	_, err := t.repo.DeleteStaleLoginThrottles(ctx, time.Now().Add(-loginFailureWindow))
+	return err
+}
+
+// Run calls Prune every interval until ctx is cancelled.
+func (t *LoginThrottle) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.Prune(ctx); err != nil {
+				slog.Error("pruning login throttles", "error", err)
+			}
+		}
+	}
+}
+
+// lockout returns how long to lock a key out after its failures-th failure:
+// nothing below limit, then BaseLockout doubling per failure up to MaxLockout.
+func (t *LoginThrottle) lockout(failures, limit int) time.Duration {
+	if limit <= 0 || failures < limit {
+		return 0
+	}
+
+	lockout := t.cfg.BaseLockout
+	for i := limit; i < failures && lockout < t.cfg.MaxLockout; i++ {
+		lockout *= 2
+	}
+	return min(lockout, t.cfg.MaxLockout)
+}
+
+func (t *LoginThrottle) keys(email, ip string) []string {
+	if ip == "" {
+		return []string{emailKey(email)}
+	}
+	return []string{emailKey(email), ipKey(ip)}
+}
+
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// dummyPasswordHash is compared against when a login names an unknown email,
+// so the response takes as long as a wrong password for a real account.
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, err := bcrypt.GenerateFromPassword([]byte("not-a-real-password"), bcrypt.DefaultCost)
+	if err != nil {
+		panic(err)
+	}
+	return hash
+})
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLockout(t *testing.T) {
+	throttle := NewLoginThrottle(nil, LoginThrottleConfig{
+		MaxFailures:   5,
+		MaxIPFailures: 50,
+		BaseLockout:   time.Minute,
+		MaxLockout:    time.Hour,
+	})
+
+	tests := []struct {
+		failures int
+		limit    int
+		want     time.Duration
+	}{
+		{0, 5, 0},
+		{4, 5, 0},
+		{5, 5, time.Minute},
+		{6, 5, 2 * time.Minute},
+		{7, 5, 4 * time.Minute},
+		{10, 5, 32 * time.Minute},
+		{11, 5, time.Hour}, // 64m capped
+		{12, 5, time.Hour},
+		{1000, 5, time.Hour}, // no overflow
+
+		{49, 50, 0},
+		{50, 50, time.Minute},
+		{52, 50, 4 * time.Minute},
+
+		{1, 1, time.Minute},
+		{100, 0, 0}, // limit disabled
+		{100, -1, 0},
+	}
+	for _, tt := range tests {
+		if got := throttle.lockout(tt.failures, tt.limit); got != tt.want {
+			t.Errorf("lockout(%d, %d) = %s, want %s", tt.failures, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestLockoutUnevenMax(t *testing.T) {
+	throttle := NewLoginThrottle(nil, LoginThrottleConfig{BaseLockout: 3 * time.Minute, MaxLockout: 10 * time.Minute})
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, 3 * time.Minute},
+		{2, 6 * time.Minute},
+		{3, 10 * time.Minute}, // 12m capped
+		{4, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := throttle.lockout(tt.failures, 1); got != tt.want {
+			t.Errorf("lockout(%d, 1) = %s, want %s", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestLoginFailureWindowOutlastsLockout(t *testing.T) {
+	// failures must still count when a maximal lockout ends, or backoff resets
+	if defaultMax := time.Hour; loginFailureWindow <= defaultMax {
+		t.Errorf("loginFailureWindow = %s, want longer than the default max lockout %s", loginFailureWindow, defaultMax)
+	}
+}
+
+func TestThrottleKeys(t *testing.T) {
+	tests := []struct {
+		email, ip string
+		want      []string
+	}{
+		{"Alice@Example.com ", "203.0.113.5", []string{"email:alice@example.com", "ip:203.0.113.5"}},
+		{"alice@example.com", "", []string{"email:alice@example.com"}},
+	}
+	for _, tt := range tests {
+		if got := (&LoginThrottle{}).keys(tt.email, tt.ip); !slices.Equal(got, tt.want) {
+			t.Errorf("keys(%q, %q) = %q, want %q", tt.email, tt.ip, got, tt.want)
+		}
+	}
+}
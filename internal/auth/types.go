@@ -1,6 +1,49 @@
 package auth
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+// ── Configuration ─────────────────────────────────────────────────────────────
+
+// Config holds the token settings for the auth Service.
+type Config struct {
+	Keys            *KeySet       // signs access tokens
+	AccessTokenTTL  time.Duration // lifetime of the JWT access token
+	RefreshTokenTTL time.Duration // lifetime of each opaque refresh token
+
+	PasswordResetTTL time.Duration // lifetime of an emailed reset token
+	PasswordResetURL string        // front-end page the reset link points at; ?token= is appended
+
+	EmailVerificationTTL       time.Duration // lifetime of an emailed verification token
+	EmailVerificationURL       string        // verification link target; ?token= is appended
+	VerificationResendCooldown time.Duration // minimum gap between two verification emails
+
+	MagicLinkTTL time.Duration // lifetime of an emailed login link
+	MagicLinkURL string        // login link target; ?token= is appended
+
+	EmailChangeTTL time.Duration // lifetime of an emailed email-change confirmation
+	EmailChangeURL string        // confirmation link target; ?token= is appended
+
+	AccountDeletionGrace time.Duration // how long a deleted account waits before it is purged
+
+	Secrets         *SecretBox    // encrypts TOTP secrets at rest
+	TOTPIssuer      string        // issuer shown in authenticator apps
+	MFAChallengeTTL time.Duration // how long an mfa_pending token may be exchanged
+
+	OIDC         *OIDCProviders // external sign-in providers; nil or empty disables OIDC
+	OIDCStateTTL time.Duration  // how long a started OIDC login may take to come back
+
+	Logins LoginRecorder // observes sign-in outcomes, e.g. for metrics; may be nil
+	Tasks  TaskRunner    // runs email deliveries; nil starts untracked goroutines
+}
+
+// TaskRunner runs work that outlives the request that started it, such as
+// sending an email, and lets shutdown wait for it to finish.
+type TaskRunner interface {
+	Do(task func())
+}
 
 // ── Domain model ─────────────────────────────────────────────────────────────
 
@@ -12,22 +55,271 @@ type User struct {
 	Password       string // bcrypt hash
 	ProfilePicture string
 	CreatedAt      string
+	TokenVersion   int32 // bumped by LogoutAll; older access tokens are rejected
+	// EmailVerifiedAt is zero until the user follows the verification link.
+	EmailVerifiedAt time.Time
+	Role            string    // RoleUser or RoleAdmin
+	LockedAt        time.Time // zero unless an admin locked the account
+	// PasswordResetRequired blocks login until the password is reset by email.
+	PasswordResetRequired bool
+	// DeletedAt is set when the user deletes the account, which is purged once
+	// the grace period has passed; zero otherwise.
+	DeletedAt time.Time
+}
+
+// ActionDeleteAccount is recorded in the audit log when a user deletes their
+// account; actor and target are both the user.
+const ActionDeleteAccount = "account.delete"
+
+// RefreshToken is a stored refresh token. The raw token is never persisted —
+// only its hash. UsedAt is set once the token has been rotated; RevokedAt once
+// its family has been revoked. Zero times mean "not yet".
+type RefreshToken struct {
+	ID        string
+	UserID    string
+	FamilyID  string
+	ExpiresAt time.Time
+	UsedAt    time.Time
+	RevokedAt time.Time
+}
+
+// Session is one login: a refresh-token family and the access tokens issued
+// with it. Its ID is the family ID and the access token's sid claim.
+type Session struct {
+	ID         string
+	UserID     string
+	UserAgent  string
+	IP         string
+	DeviceName string // approximate, derived from UserAgent
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
+
+// PasswordResetToken is a stored password reset token (hash only). A zero
+// UsedAt means it has not been redeemed.
+type PasswordResetToken struct {
+	ID        string
+	UserID    string
+	ExpiresAt time.Time
+	UsedAt    time.Time
+}
+
+// EmailVerificationToken is a stored email verification token (hash only). A
+// zero UsedAt means it has not been redeemed.
+type EmailVerificationToken struct {
+	ID        string
+	UserID    string
+	ExpiresAt time.Time
+	UsedAt    time.Time
+}
+
+// EmailChangeToken is a stored email change confirmation (hash only). A zero
+// UsedAt means it has not been redeemed.
+type EmailChangeToken struct {
+	ID        string
+	UserID    string
+	NewEmail  string
+	ExpiresAt time.Time
+	UsedAt    time.Time
+}
+
+// MagicLinkToken is a stored passwordless login token (hash only). BrowserHash
+// is the hash of the nonce cookie given to the browser that requested it.
+type MagicLinkToken struct {
+	ID          string
+	UserID      string
+	BrowserHash string
+	ExpiresAt   time.Time
+	UsedAt      time.Time
+}
+
+// VerificationSendStats summarises the verification emails sent to a user in a window.
+type VerificationSendStats struct {
+	Sent       int
+	LastSentAt time.Time // zero when none were sent
+}
+
+// TOTPEnrollment is a user's TOTP secret, still encrypted. A zero ConfirmedAt
+// means setup was started but no code has been verified yet, so 2FA is off.
+type TOTPEnrollment struct {
+	UserID          string
+	SecretEncrypted []byte
+	ConfirmedAt     time.Time
+	LastUsedStep    int64
+}
+
+// MFAChallenge is a pending second-factor check issued by Login.
+type MFAChallenge struct {
+	ID        string
+	UserID    string
+	Attempts  int
+	ExpiresAt time.Time
+	UsedAt    time.Time
+}
+
+// APIKey is a stored personal access token (hash only). Zero ExpiresAt means
+// it never expires; zero LastUsedAt means it has not been used yet.
+type APIKey struct {
+	ID         string
+	UserID     string
+	Name       string
+	Prefix     string // first characters of the key, to tell keys apart
+	Scopes     []string
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+	CreatedAt  time.Time
+}
+
+// OIDCLoginState is an authorization code flow started by StartOIDC and not
+// yet completed. It is looked up by the hash of the state parameter.
+type OIDCLoginState struct {
+	Provider     string
+	Nonce        string
+	CodeVerifier string // PKCE verifier; only its S256 challenge was sent out
+	ExpiresAt    time.Time
 }
 
 // ── Service DTOs ──────────────────────────────────────────────────────────────
 
+// ClientInfo describes the caller of a request that may start or extend a
+// session. IP is also used for per-IP brute-force limits.
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
 // RegisterInput is the DTO passed from handler → service for registration.
 type RegisterInput struct {
 	Name           string
 	Email          string
 	Password       string
 	ProfilePicture string
+	Client         ClientInfo
 }
 
 // LoginInput is the DTO passed from handler → service for login.
 type LoginInput struct {
 	Email    string
 	Password string
+	Client   ClientInfo
+}
+
+// RefreshInput is the DTO passed from handler → service to rotate a refresh token.
+type RefreshInput struct {
+	RefreshToken string
+	Client       ClientInfo
+}
+
+// LogoutInput identifies the access token (and its session) being logged out.
+// It is built by the handler from the verified claims.
+type LogoutInput struct {
+	UserID    string
+	TokenID   string // the access token's jti
+	SessionID string // refresh-token family the access token was issued with
+	ExpiresAt time.Time
+}
+
+// ForgotPasswordInput is the DTO passed from handler → service to request a reset email.
+type ForgotPasswordInput struct {
+	Email string
+}
+
+// ResetPasswordInput is the DTO passed from handler → service to redeem a reset token.
+type ResetPasswordInput struct {
+	Token       string
+	NewPassword string
+}
+
+// ChangePasswordInput is the DTO passed from handler → service to change a
+// signed-in user's password. SessionID is the caller's session, which stays
+// signed in.
+type ChangePasswordInput struct {
+	UserID          string
+	SessionID       string
+	CurrentPassword string
+	NewPassword     string
+	Client          ClientInfo
+}
+
+// ChangeEmailInput is the DTO passed from handler → service to request an
+// email address change.
+type ChangeEmailInput struct {
+	UserID   string
+	NewEmail string
+	Password string
+	Client   ClientInfo
+}
+
+// DeleteAccountInput is the DTO passed from handler → service to delete the
+// caller's account.
+type DeleteAccountInput struct {
+	UserID   string
+	Password string
+	Client   ClientInfo
+}
+
+// ConfirmEmailChangeInput is the DTO passed from handler → service to redeem
+// an email change confirmation.
+type ConfirmEmailChangeInput struct {
+	Token string
+}
+
+// VerifyEmailInput is the DTO passed from handler → service to redeem a verification token.
+type VerifyEmailInput struct {
+	Token string
+}
+
+// MagicLinkInput is the DTO passed from handler → service to email a login link.
+type MagicLinkInput struct {
+	Email        string
+	BrowserNonce string // value of the cookie set on the requesting browser
+}
+
+// ConsumeMagicLinkInput is the DTO passed from handler → service to redeem a login link.
+type ConsumeMagicLinkInput struct {
+	Token        string
+	BrowserNonce string // empty when the browser sent no cookie
+	Client       ClientInfo
+}
+
+// ConfirmTOTPInput is the DTO passed from handler → service to finish TOTP enrollment.
+type ConfirmTOTPInput struct {
+	UserID string
+	Code   string
+}
+
+// VerifyMFAInput is the DTO passed from handler → service to complete a login.
+// Exactly one of Code and RecoveryCode is set.
+type VerifyMFAInput struct {
+	MFAToken     string
+	Code         string
+	RecoveryCode string
+	Client       ClientInfo
+}
+
+// CreateAPIKeyInput is the DTO passed from handler → service to mint an API key.
+type CreateAPIKeyInput struct {
+	UserID    string
+	Name      string
+	Scopes    []string
+	ExpiresAt time.Time // zero means the key never expires
+}
+
+// OIDCAuthRequest is returned by StartOIDC. The handler redirects the browser
+// to URL and binds State to it with a cookie.
+type OIDCAuthRequest struct {
+	URL       string
+	State     string
+	ExpiresIn time.Duration
+}
+
+// OIDCCallbackInput is the DTO passed from handler → service when the
+// provider redirects back with an authorization code.
+type OIDCCallbackInput struct {
+	Provider string
+	State    string
+	Code     string
+	Client   ClientInfo
 }
 
 // UserPayload is the public user object embedded in auth responses.
@@ -36,13 +328,89 @@ type UserPayload struct {
 	Name           string `json:"name"`
 	Email          string `json:"email"`
 	ProfilePicture string `json:"profile_picture,omitempty"`
+	EmailVerified  bool   `json:"email_verified"`
+	Role           string `json:"role"`
 	CreatedAt      string `json:"created_at"`
 }
 
-// AuthResponse is returned after a successful register or login.
+// AuthResponse is returned after a successful register, login or refresh.
 type AuthResponse struct {
-	AccessToken string      `json:"access_token"`
-	User        UserPayload `json:"user"`
+	AccessToken  string      `json:"access_token"`
+	RefreshToken string      `json:"refresh_token"`
+	ExpiresIn    int64       `json:"expires_in"` // access token lifetime in seconds
+	User         UserPayload `json:"user"`
+}
+
+// LoginResult is returned by Login: tokens, or an MFA challenge when the
+// account has two-factor authentication enabled. Exactly one is set.
+type LoginResult struct {
+	Auth *AuthResponse
+	MFA  *MFAChallengeResponse
+}
+
+// MFAChallengeResponse is returned instead of tokens when a second factor is required.
+type MFAChallengeResponse struct {
+	Status    string `json:"status"` // always "mfa_pending"
+	MFAToken  string `json:"mfa_token"`
+	ExpiresIn int64  `json:"expires_in"` // seconds
+}
+
+// TOTPSetupResponse carries a new, unconfirmed TOTP secret.
+type TOTPSetupResponse struct {
+	Secret     string `json:"secret"` // base32, for manual entry
+	OTPAuthURL string `json:"otpauth_url"`
+	QRCodePNG  []byte `json:"qr_code_png"` // base64 in JSON
+}
+
+// RecoveryCodesResponse lists freshly generated recovery codes. They are
+// shown once; only their hashes are stored.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// SessionPayload is the public view of a session.
+type SessionPayload struct {
+	ID         string    `json:"id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"` // the session of the calling access token
+}
+
+// SessionListResponse lists a user's active sessions, most recently seen first.
+type SessionListResponse struct {
+	Sessions []SessionPayload `json:"sessions"`
+}
+
+// APIKeyPayload is the public view of an API key. The key itself is never
+// shown again after creation.
+type APIKeyPayload struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse is returned once, when a key is minted.
+type CreatedAPIKeyResponse struct {
+	APIKeyPayload
+	Key string `json:"key"`
+}
+
+// APIKeyListResponse lists a user's API keys, newest first.
+type APIKeyListResponse struct {
+	APIKeys []APIKeyPayload `json:"api_keys"`
+}
+
+// DeleteAccountResponse tells a user when their deleted account will be purged.
+type DeleteAccountResponse struct {
+	DeletedAt  time.Time `json:"deleted_at"`
+	PurgeAfter time.Time `json:"purge_after"`
 }
 
 // ── Repository DTO ────────────────────────────────────────────────────────────
@@ -57,17 +425,271 @@ type CreateUserParams struct {
 	ProfilePicture string
 }
 
+// CreateRefreshTokenParams carries a new refresh token's hash and family.
+type CreateRefreshTokenParams struct {
+	ID        string
+	UserID    string
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+}
+
+// CreateSessionParams carries a new session's client details.
+type CreateSessionParams struct {
+	ID         string
+	UserID     string
+	UserAgent  string
+	IP         string
+	DeviceName string
+}
+
+// CreatePasswordResetTokenParams carries a new reset token's hash.
+type CreatePasswordResetTokenParams struct {
+	ID        string
+	UserID    string
+	TokenHash string
+	ExpiresAt time.Time
+}
+
+// CreateEmailVerificationTokenParams carries a new verification token's hash.
+type CreateEmailVerificationTokenParams struct {
+	ID        string
+	UserID    string
+	TokenHash string
+	ExpiresAt time.Time
+}
+
+// CreateEmailChangeTokenParams carries a new email change confirmation's hash.
+type CreateEmailChangeTokenParams struct {
+	ID        string
+	UserID    string
+	NewEmail  string
+	TokenHash string
+	ExpiresAt time.Time
+}
+
+// CreateMagicLinkTokenParams carries a new login link's hash and browser binding.
+type CreateMagicLinkTokenParams struct {
+	ID          string
+	UserID      string
+	TokenHash   string
+	BrowserHash string
+	ExpiresAt   time.Time
+}
+
+// CreateMFAChallengeParams carries a new MFA challenge's hash.
+type CreateMFAChallengeParams struct {
+	ID        string
+	UserID    string
+	TokenHash string
+	ExpiresAt time.Time
+}
+
+// CreateAPIKeyParams carries a new API key's hash and metadata.
+type CreateAPIKeyParams struct {
+	ID        string
+	UserID    string
+	Name      string
+	Prefix    string
+	KeyHash   string
+	Scopes    []string
+	ExpiresAt time.Time // zero means no expiry
+}
+
+// CreateOIDCLoginStateParams carries a started OIDC login.
+type CreateOIDCLoginStateParams struct {
+	StateHash    string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+}
+
+// CreateUserIdentityParams links a provider subject to a local user.
+type CreateUserIdentityParams struct {
+	Provider string
+	Subject  string
+	UserID   string
+	Email    string
+}
+
 // ── Contracts ─────────────────────────────────────────────────────────────────
 
 // Repository defines the data-access contract for the auth domain.
 // All method signatures use domain types only.
 type Repository interface {
 	CreateUser(ctx context.Context, params CreateUserParams) (User, error)
+	// GetUserByEmail and GetUserByID return ErrUserNotFound if no user matches.
 	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserByID(ctx context.Context, id string) (User, error)
+
+	CreateRefreshToken(ctx context.Context, params CreateRefreshTokenParams) error
+	// GetRefreshTokenForUpdate locks the token row for the rest of the
+	// ambient transaction. Returns ErrInvalidRefreshToken if none matches.
+	GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, id string) error
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string) error
+	RevokeUserRefreshTokens(ctx context.Context, userID string) error
+
+	CreateSession(ctx context.Context, params CreateSessionParams) error
+	// TouchSession records activity and reports whether the session is still active.
+	TouchSession(ctx context.Context, id string) (bool, error)
+	RefreshSession(ctx context.Context, id, ip string) error
+	// ListSessions returns the user's unrevoked sessions seen after since.
+	ListSessions(ctx context.Context, userID string, since time.Time) ([]Session, error)
+	RevokeSession(ctx context.Context, id string) error
+	// RevokeUserSession returns ErrSessionNotFound unless userID owns the active session.
+	RevokeUserSession(ctx context.Context, id, userID string) error
+	RevokeUserSessions(ctx context.Context, userID string) error
+
+	RevokeAccessToken(ctx context.Context, jti, userID string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+	DeleteExpiredRevokedTokens(ctx context.Context) (int64, error)
+	// GetTokenVersion returns ErrTokenRevoked if the user no longer exists.
+	GetTokenVersion(ctx context.Context, userID string) (int32, error)
+	IncrementTokenVersion(ctx context.Context, userID string) (int32, error)
+
+	UpdatePassword(ctx context.Context, userID, passwordHash string) error
+	// LockUser, UnlockUser and RequirePasswordReset return ErrUserNotFound if no user matches.
+	LockUser(ctx context.Context, userID string) error
+	UnlockUser(ctx context.Context, userID string) error
+	RequirePasswordReset(ctx context.Context, userID string) error
+	// SoftDeleteUser marks the user deleted and returns when that first
+	// happened. RestoreUser clears the mark, or returns ErrAccountPurging once
+	// the purge has started. Both return ErrUserNotFound if no user matches.
+	SoftDeleteUser(ctx context.Context, userID string) (time.Time, error)
+	RestoreUser(ctx context.Context, userID string) error
+	CreateAuditEntry(ctx context.Context, id, actorID, action, targetUserID string) error
+	CreatePasswordResetToken(ctx context.Context, params CreatePasswordResetTokenParams) error
+	// GetPasswordResetTokenForUpdate locks the token row for the rest of the
+	// ambient transaction. Returns ErrInvalidResetToken if none matches.
+	GetPasswordResetTokenForUpdate(ctx context.Context, tokenHash string) (PasswordResetToken, error)
+	// ConsumePasswordResetTokens marks every outstanding reset token of the user as used.
+	ConsumePasswordResetTokens(ctx context.Context, userID string) error
+
+	MarkEmailVerified(ctx context.Context, userID string) error
+	IsEmailVerified(ctx context.Context, userID string) (bool, error)
+	CreateEmailVerificationToken(ctx context.Context, params CreateEmailVerificationTokenParams) error
+	// GetEmailVerificationTokenForUpdate locks the token row for the rest of
+	// the ambient transaction. Returns ErrInvalidVerificationToken if none matches.
+	GetEmailVerificationTokenForUpdate(ctx context.Context, tokenHash string) (EmailVerificationToken, error)
+	ConsumeEmailVerificationTokens(ctx context.Context, userID string) error
+	GetVerificationSendStats(ctx context.Context, userID string, since time.Time) (VerificationSendStats, error)
+
+	CreateMagicLinkToken(ctx context.Context, params CreateMagicLinkTokenParams) error
+	// GetMagicLinkTokenForUpdate locks the token row for the rest of the
+	// ambient transaction. Returns ErrInvalidMagicLink if none matches.
+	GetMagicLinkTokenForUpdate(ctx context.Context, tokenHash string) (MagicLinkToken, error)
+	// ConsumeMagicLinkTokens marks every outstanding login link of the user as used.
+	ConsumeMagicLinkTokens(ctx context.Context, userID string) error
+	CountMagicLinksSince(ctx context.Context, userID string, since time.Time) (int, error)
+
+	// UpdateEmail sets a confirmed address. Returns ErrEmailTaken if another
+	// account has it.
+	UpdateEmail(ctx context.Context, userID, email string) error
+	CreateEmailChangeToken(ctx context.Context, params CreateEmailChangeTokenParams) error
+	// GetEmailChangeTokenForUpdate locks the token row for the rest of the
+	// ambient transaction. Returns ErrInvalidEmailChangeToken if none matches.
+	GetEmailChangeTokenForUpdate(ctx context.Context, tokenHash string) (EmailChangeToken, error)
+	// ConsumeEmailChangeTokens marks every outstanding email change token of the user as used.
+	ConsumeEmailChangeTokens(ctx context.Context, userID string) error
+	CountEmailChangesSince(ctx context.Context, userID string, since time.Time) (int, error)
+
+	// UpsertPendingTOTP starts or restarts enrollment; a confirmed enrollment is left alone.
+	UpsertPendingTOTP(ctx context.Context, userID string, secretEncrypted []byte) error
+	// GetTOTP and GetTOTPForUpdate return ErrTOTPNotEnrolled if the user never started setup.
+	GetTOTP(ctx context.Context, userID string) (TOTPEnrollment, error)
+	GetTOTPForUpdate(ctx context.Context, userID string) (TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, userID string, step int64) error
+	UpdateTOTPLastUsedStep(ctx context.Context, userID string, step int64) error
+	DeleteRecoveryCodes(ctx context.Context, userID string) error
+	CreateRecoveryCode(ctx context.Context, id, userID, codeHash string) error
+	// UseRecoveryCode marks an unused code as used and reports whether one matched.
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+	CreateMFAChallenge(ctx context.Context, params CreateMFAChallengeParams) error
+	// GetMFAChallengeForUpdate returns ErrInvalidMFAChallenge if none matches.
+	GetMFAChallengeForUpdate(ctx context.Context, tokenHash string) (MFAChallenge, error)
+	IncrementMFAChallengeAttempts(ctx context.Context, id string) error
+	MarkMFAChallengeUsed(ctx context.Context, id string) error
+
+	// GetLoginLockedUntil returns the latest lockout in force among keys, or zero.
+	GetLoginLockedUntil(ctx context.Context, keys []string) (time.Time, error)
+	// RecordLoginFailure returns the key's failure count, restarting at 1 when
+	// the previous failure is older than resetBefore.
+	RecordLoginFailure(ctx context.Context, key string, resetBefore time.Time) (int, error)
+	LockLoginKey(ctx context.Context, key string, until time.Time) error
+	ClearLoginFailures(ctx context.Context, key string) error
+	DeleteStaleLoginThrottles(ctx context.Context, before time.Time) (int64, error)
+
+	CreateAPIKey(ctx context.Context, params CreateAPIKeyParams) (APIKey, error)
+	ListAPIKeys(ctx context.Context, userID string) ([]APIKey, error)
+	// GetAPIKeyByHash returns ErrInvalidAPIKey if none matches.
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (APIKey, error)
+	// TouchAPIKey records a use; writes are coalesced to one per minute.
+	TouchAPIKey(ctx context.Context, id string) error
+	// DeleteAPIKey returns ErrAPIKeyNotFound unless userID owns the key.
+	DeleteAPIKey(ctx context.Context, id, userID string) error
+
+	CreateOIDCLoginState(ctx context.Context, params CreateOIDCLoginStateParams) error
+	// ConsumeOIDCLoginState deletes and returns the state, so it works once.
+	// Returns ErrInvalidOIDCState if none matches.
+	ConsumeOIDCLoginState(ctx context.Context, stateHash string) (OIDCLoginState, error)
+	DeleteExpiredOIDCLoginStates(ctx context.Context) (int64, error)
+	// GetUserByIdentity returns ErrUserNotFound if the subject is not linked.
+	GetUserByIdentity(ctx context.Context, provider, subject string) (User, error)
+	CreateUserIdentity(ctx context.Context, params CreateUserIdentityParams) error
+	// TouchUserIdentity records a sign-in and the email the provider reported.
+	TouchUserIdentity(ctx context.Context, provider, subject, email string) error
+}
+
+// TxRunner runs fn as a single unit of work. Repository calls made with the
+// ctx passed to fn join the same database transaction.
+type TxRunner interface {
+	Atomic(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 // Service defines the business-logic contract for the auth domain.
 type Service interface {
 	Register(ctx context.Context, input RegisterInput) (AuthResponse, error)
-	Login(ctx context.Context, input LoginInput) (AuthResponse, error)
+	Login(ctx context.Context, input LoginInput) (LoginResult, error)
+	Refresh(ctx context.Context, input RefreshInput) (AuthResponse, error)
+	Logout(ctx context.Context, input LogoutInput) error
+	LogoutAll(ctx context.Context, userID string) error
+	// ListSessions marks currentID, the caller's own session, as current.
+	ListSessions(ctx context.Context, userID, currentID string) (SessionListResponse, error)
+	RevokeSession(ctx context.Context, userID, id string) error
+	ForgotPassword(ctx context.Context, input ForgotPasswordInput) error
+	ResetPassword(ctx context.Context, input ResetPasswordInput) error
+	ChangePassword(ctx context.Context, input ChangePasswordInput) error
+	RequestEmailChange(ctx context.Context, input ChangeEmailInput) error
+	ConfirmEmailChange(ctx context.Context, input ConfirmEmailChangeInput) error
+	DeleteAccount(ctx context.Context, input DeleteAccountInput) (DeleteAccountResponse, error)
+	RequestMagicLink(ctx context.Context, input MagicLinkInput) error
+	ConsumeMagicLink(ctx context.Context, input ConsumeMagicLinkInput) (LoginResult, error)
+	VerifyEmail(ctx context.Context, input VerifyEmailInput) error
+	ResendVerification(ctx context.Context, userID string) error
+	IsEmailVerified(ctx context.Context, userID string) (bool, error)
+	SetupTOTP(ctx context.Context, userID string) (TOTPSetupResponse, error)
+	ConfirmTOTP(ctx context.Context, input ConfirmTOTPInput) (RecoveryCodesResponse, error)
+	VerifyMFA(ctx context.Context, input VerifyMFAInput) (AuthResponse, error)
+	CreateAPIKey(ctx context.Context, input CreateAPIKeyInput) (CreatedAPIKeyResponse, error)
+	ListAPIKeys(ctx context.Context, userID string) (APIKeyListResponse, error)
+	DeleteAPIKey(ctx context.Context, userID, id string) error
+	AuthenticateAPIKey(ctx context.Context, key string) (APIKey, error)
+	StartOIDC(ctx context.Context, provider string) (OIDCAuthRequest, error)
+	CompleteOIDC(ctx context.Context, input OIDCCallbackInput) (LoginResult, error)
+
+	// LockAccount, UnlockAccount, RequirePasswordReset and RestoreAccount back
+	// the admin API. Each runs audit in its own transaction, and updates caches
+	// or sends email only once that has committed. They return ErrUserNotFound
+	// for unknown users.
+	LockAccount(ctx context.Context, userID string, audit AuditFunc) error
+	UnlockAccount(ctx context.Context, userID string, audit AuditFunc) error
+	RequirePasswordReset(ctx context.Context, userID string, audit AuditFunc) error
+	RestoreAccount(ctx context.Context, userID string, audit AuditFunc) error
 }
+
+// AuditFunc records an account change in the caller's audit log. It is run
+// with the ctx of the change's transaction, so both commit or neither does,
+// and may run more than once if the transaction is retried.
+type AuditFunc func(ctx context.Context) error
@@ -0,0 +1,55 @@
+// Package blobstore stores binary objects (avatar images, data exports) behind a pluggable
+// BlobStore, with local-filesystem and S3-compatible implementations.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by Get for a key that holds no object.
+var ErrNotFound = errors.New("blobstore: object not found")
+
+// Object is a stored blob opened for reading. The caller must close Body.
+type Object struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+}
+
+// BlobStore keeps objects under slash-separated keys such as
+// "avatars/<user>/<version>/128.jpg". Implementations must be safe for
+// concurrent use, and implement health.HealthChecker.
+type BlobStore interface {
+	// Put stores size bytes read from body under key, replacing any object there.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get returns ErrNotFound if nothing is stored under key.
+	Get(ctx context.Context, key string) (Object, error)
+	// Delete removes the object under key; deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix removes every object whose key starts with prefix + "/".
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// keyPattern allows path segments of letters, digits, '.', '_' and '-'.
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+(/[A-Za-z0-9._-]+)*$`)
+
+// validateKey rejects keys that could escape the store's root, so
+// implementations can map keys straight onto paths.
+func validateKey(key string) error {
+	if !keyPattern.MatchString(key) {
+		return fmt.Errorf("blobstore: invalid key %q", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "." || segment == ".." {
+			return fmt.Errorf("blobstore: invalid key %q", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package clientip
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	trusted, err := ParseTrusted([]string{"10.0.0.0/8", "192.0.2.10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		remote  string
+		xff     []string
+		realIP  string
+		trusted bool // use the trusted list; otherwise none
+		want    string
+	}{
+		{"no proxies ignores headers", "203.0.113.5:4000", []string{"198.51.100.1"}, "198.51.100.2", false, "203.0.113.5"},
+		{"untrusted peer ignores headers", "203.0.113.5:4000", []string{"198.51.100.1"}, "198.51.100.2", true, "203.0.113.5"},
+		{"trusted peer, one hop", "10.1.2.3:4000", []string{"198.51.100.1"}, "", true, "198.51.100.1"},
+		{"forged entry on the left is skipped", "10.1.2.3:4000", []string{"6.6.6.6, 198.51.100.1"}, "", true, "198.51.100.1"},
+		{"trusted hops are skipped", "10.1.2.3:4000", []string{"198.51.100.1, 192.0.2.10, 10.9.9.9"}, "", true, "198.51.100.1"},
+		{"repeated headers are one list", "10.1.2.3:4000", []string{"6.6.6.6", "198.51.100.1"}, "", true, "198.51.100.1"},
+		{"all hops trusted", "10.1.2.3:4000", []string{"10.0.0.1, 10.0.0.2"}, "", true, "10.0.0.1"},
+		{"garbage stops the walk", "10.1.2.3:4000", []string{"6.6.6.6, junk, 10.0.0.2"}, "", true, "10.0.0.2"},
+		{"X-Real-IP from a trusted peer", "192.0.2.10:4000", nil, "198.51.100.2", true, "198.51.100.2"},
+		{"bad X-Real-IP keeps the peer", "192.0.2.10:4000", nil, "junk", true, "192.0.2.10"},
+		{"IPv6 peer", "[2001:db8::1]:4000", []string{"198.51.100.1"}, "", true, "2001:db8::1"},
+		{"no port", "203.0.113.5", nil, "", false, "203.0.113.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remote
+			for _, v := range tt.xff {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			list := trusted
+			if !tt.trusted {
+				list = nil
+			}
+			if got := Resolve(r, list); got.String() != tt.want {
+				t.Errorf("Resolve = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrusted(t *testing.T) {
+	if _, err := ParseTrusted([]string{"10.0.0.0/8", "::1", "2001:db8::/32"}); err != nil {
+		t.Errorf("ParseTrusted error = %v", err)
+	}
+	for _, bad := range []string{"10.0.0.0/33", "example.com", ""} {
+		if _, err := ParseTrusted([]string{bad}); err == nil {
+			t.Errorf("ParseTrusted(%q) succeeded, want an error", bad)
+		}
+	}
+}
@@ -1,11 +1,259 @@
+// Package env reads the API's settings. Every setting has an upper-case key,
+// such as JWT_SECRET, and is looked up in order of precedence in:
+//
+//  1. command-line flags: --jwt-secret=... (lower case, '-' for '_')
+//  2. environment variables: JWT_SECRET=...
+//  3. a YAML file named by --config-file or CONFIG_FILE, either flat
+//     (jwt_secret: ...) or nested (jwt: {secret: ...})
+//  4. the fallback passed to the getter
+//
+// In each of the first three, KEY_FILE may name a file holding the value
+// instead, for secrets mounted by Docker or Kubernetes. Empty values count as
+// unset.
 package env
 
-import "os"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
 
-func GetString(key, fallback string) string {
-	if val := os.Getenv(key); val != "" {
-		return val
+// Loader resolves settings from its sources and remembers every setting it
+// was asked for, so they can be printed and checked afterwards.
+type Loader struct {
+	sources  []source // highest precedence first
+	aliases  map[string]string
+	settings map[string]Setting
+	consumed map[string]bool // keys looked up, including KEY_FILE and aliases
+	errs     []error
+}
+
+// Setting is one setting as resolved by a Loader.
+type Setting struct {
+	Key    string
+	Value  string
+	Source string // "flag", "env", "config file" or "default"
+	Secret bool
+}
+
+// source is one layer of settings.
+type source struct {
+	name   string
+	lookup func(key string) (string, bool)
+	keys   []string // every key it sets; nil when it cannot tell (the environment)
+}
+
+// Load builds a Loader from command-line args and the environment, reading
+// the config file if one is named. args are --key=value flags; a bare --key
+// means --key=true.
+func Load(args []string) (*Loader, error) {
+	flags, err := parseFlags(args)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Loader{
+		aliases:  make(map[string]string),
+		settings: make(map[string]Setting),
+		consumed: make(map[string]bool),
 	}
+	l.sources = append(l.sources, mapSource("flag", flags), source{name: "env", lookup: lookupEnv})
+
+	if path := l.String("CONFIG_FILE", ""); path != "" {
+		file, err := readYAML(path)
+		if err != nil {
+			return nil, err
+		}
+		l.sources = append(l.sources, mapSource("config file", file))
+	}
+	return l, nil
+}
+
+// Alias makes key fall back to old when key is set nowhere, so renamed
+// settings keep working.
+func (l *Loader) Alias(key, old string) {
+	l.aliases[key] = old
+}
+
+// String returns the setting key, or fallback when it is not set.
+func (l *Loader) String(key, fallback string) string {
+	return l.get(key, fallback, false)
+}
+
+// Secret is String for values that must not be shown, such as passwords.
+func (l *Loader) Secret(key, fallback string) string {
+	return l.get(key, fallback, true)
+}
 
-	return fallback
-}
\ No newline at end of file
+// Int returns the setting key as an integer, or fallback when it is not set.
+func (l *Loader) Int(key string, fallback int) int {
+	val := l.get(key, strconv.Itoa(fallback), false)
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: %q is not an integer", key, val))
+		return fallback
+	}
+	return n
+}
+
+// Duration returns the setting key as a time.Duration such as "15m", or
+// fallback when it is not set.
+func (l *Loader) Duration(key string, fallback time.Duration) time.Duration {
+	val := l.get(key, fallback.String(), false)
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: %q is not a duration, e.g. 90s or 15m", key, val))
+		return fallback
+	}
+	return d
+}
+
+// Bool returns the setting key as a boolean (true/false, 1/0), or fallback
+// when it is not set.
+func (l *Loader) Bool(key string, fallback bool) bool {
+	val := l.get(key, strconv.FormatBool(fallback), false)
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: %q is not true or false", key, val))
+		return fallback
+	}
+	return b
+}
+
+// List returns the setting key split on commas, without empty items, or
+// fallback when it is not set. In the config file it may also be a list.
+func (l *Loader) List(key string, fallback []string) []string {
+	val := l.get(key, strings.Join(fallback, ","), false)
+	var out []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// Err reports values that could not be parsed, KEY_FILE files that could not
+// be read, and flags or config file keys that no setting uses — usually typos.
+// Call it once every setting has been read.
+func (l *Loader) Err() error {
+	errs := slices.Clone(l.errs)
+	for _, src := range l.sources {
+		for _, key := range src.keys {
+			if !l.consumed[key] {
+				errs = append(errs, fmt.Errorf("%s: unknown setting %s", src.name, key))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Settings returns every setting read so far, sorted by key.
+func (l *Loader) Settings() []Setting {
+	out := make([]Setting, 0, len(l.settings))
+	for _, s := range l.settings {
+		out = append(out, s)
+	}
+	slices.SortFunc(out, func(a, b Setting) int { return strings.Compare(a.Key, b.Key) })
+	return out
+}
+
+// Print writes every setting read so far as KEY=value lines, each commented
+// with where its value came from. With redacted set, secrets are masked.
+func (l *Loader) Print(w io.Writer, redacted bool) error {
+	for _, s := range l.Settings() {
+		val := s.Value
+		if s.Secret && redacted && val != "" {
+			val = "[redacted]"
+		} else if strings.ContainsAny(val, " \t#\"'\\") {
+			val = strconv.Quote(val)
+		}
+		if _, err := fmt.Fprintf(w, "%s=%s # %s\n", s.Key, val, s.Source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// get resolves key and records it.
+func (l *Loader) get(key, fallback string, secret bool) string {
+	found, ok := l.lookup(key)
+	if old, aliased := l.aliases[key]; aliased {
+		if !ok {
+			found, ok = l.lookup(old)
+		}
+		l.consumed[old], l.consumed[old+"_FILE"] = true, true
+	}
+	if !ok {
+		found = resolved{value: fallback, source: "default", key: key}
+	}
+
+	source := found.source
+	if found.key != key {
+		source += " (" + found.key + ")"
+	}
+	l.settings[key] = Setting{Key: key, Value: found.value, Source: source, Secret: secret}
+	return found.value
+}
+
+// resolved is a value and the source and key it was found under.
+type resolved struct {
+	value  string
+	source string
+	key    string
+}
+
+// lookup finds key, or the file named by KEY_FILE, in the first source that
+// sets either.
+func (l *Loader) lookup(key string) (resolved, bool) {
+	fileKey := key + "_FILE"
+	l.consumed[key] = true
+	l.consumed[fileKey] = true
+
+	for _, src := range l.sources {
+		val, hasVal := src.lookup(key)
+		path, hasFile := src.lookup(fileKey)
+		switch {
+		case hasVal && hasFile:
+			l.errs = append(l.errs, fmt.Errorf("%s: both %s and %s are set", src.name, key, fileKey))
+			return resolved{value: val, source: src.name, key: key}, true
+		case hasVal:
+			return resolved{value: val, source: src.name, key: key}, true
+		case hasFile:
+			data, err := os.ReadFile(path)
+			if err != nil {
+				l.errs = append(l.errs, fmt.Errorf("%s: %w", fileKey, err))
+				return resolved{}, false
+			}
+			return resolved{value: strings.TrimRight(string(data), "\r\n"), source: src.name, key: fileKey}, true
+		}
+	}
+	return resolved{}, false
+}
+
+func lookupEnv(key string) (string, bool) {
+	val := os.Getenv(key)
+	return val, val != ""
+}
+
+func mapSource(name string, values map[string]string) source {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return source{
+		name: name,
+		lookup: func(key string) (string, bool) {
+			val := values[key]
+			return val, val != ""
+		},
+		keys: keys,
+	}
+}
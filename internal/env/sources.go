@@ -0,0 +1,103 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseFlags reads --key=value arguments into settings keys: --jwt-secret
+// sets JWT_SECRET. A bare --key sets it to "true".
+func parseFlags(args []string) (map[string]string, error) {
+	flags := make(map[string]string, len(args))
+	for _, arg := range args {
+		name, ok := strings.CutPrefix(arg, "--")
+		if !ok {
+			name, ok = strings.CutPrefix(arg, "-")
+		}
+		if !ok || name == "" {
+			return nil, fmt.Errorf("unexpected argument %q: settings are given as --key=value", arg)
+		}
+
+		name, val, hasVal := strings.Cut(name, "=")
+		if !hasVal {
+			val = "true"
+		}
+		flags[settingKey(name)] = val
+	}
+	return flags, nil
+}
+
+// readYAML reads a config file into settings keys. Nested mappings are joined
+// with '_', so jwt: {secret: ...} sets JWT_SECRET, and lists become
+// comma-separated values.
+func readYAML(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	if err := flatten(values, "", doc); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+func flatten(out map[string]string, prefix string, m map[string]any) error {
+	for name, v := range m {
+		key := settingKey(name)
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+
+		switch v := v.(type) {
+		case map[string]any:
+			if err := flatten(out, key, v); err != nil {
+				return err
+			}
+		case []any:
+			items := make([]string, len(v))
+			for i, item := range v {
+				s, err := scalar(key, item)
+				if err != nil {
+					return err
+				}
+				items[i] = s
+			}
+			out[key] = strings.Join(items, ",")
+		default:
+			s, err := scalar(key, v)
+			if err != nil {
+				return err
+			}
+			out[key] = s
+		}
+	}
+	return nil
+}
+
+func scalar(key string, v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case int, int64, uint64, float64, bool:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("%s: unsupported value %v", key, v)
+	}
+}
+
+// settingKey turns a flag or config file name such as jwt-secret into JWT_SECRET.
+func settingKey(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
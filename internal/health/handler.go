@@ -0,0 +1,52 @@
+package health
+
+import (
+	"net/http"
+	"time"
+
+	jsonutil "github.com/Ajay01103/goTransactonsAPI/internal/json"
+)
+
+// Handler serves the liveness and readiness probes.
+type Handler struct {
+	live    *Registry
+	ready   *Registry
+	serving func() bool
+}
+
+// NewHandler constructs a Handler. live holds checks whose failure means the
+// process should be restarted, ready those whose failure means it should get
+// no traffic; serving reports false before startup and once shutdown begins.
+func NewHandler(live, ready *Registry, serving func() bool) *Handler {
+	return &Handler{live: live, ready: ready, serving: serving}
+}
+
+// Livez handles GET /livez: 200 while the process can serve at all, 503
+// otherwise. It does not check dependencies, so a database outage does not
+// get every replica restarted.
+func (h *Handler) Livez(w http.ResponseWriter, r *http.Request) {
+	h.write(w, h.live.Run(r.Context()))
+}
+
+// Readyz handles GET /readyz: 200 when every required dependency is usable,
+// 503 otherwise or while shutting down.
+func (h *Handler) Readyz(w http.ResponseWriter, r *http.Request) {
+	if !h.serving() {
+		h.write(w, Report{
+			Status:    StatusFail,
+			Checks:    map[string]Result{"shutdown": {Status: StatusFail, Latency: "0s"}},
+			CheckedAt: time.Now(),
+		})
+		return
+	}
+	h.write(w, h.ready.Run(r.Context()))
+}
+
+func (h *Handler) write(w http.ResponseWriter, report Report) {
+	w.Header().Set("Cache-Control", "no-store")
+	status := http.StatusOK
+	if !report.OK() {
+		status = http.StatusServiceUnavailable
+	}
+	jsonutil.Write(w, status, report)
+}
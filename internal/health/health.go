@@ -0,0 +1,160 @@
+// Package health runs the checks behind the liveness and readiness probes.
+// Packages register a HealthChecker for each dependency they need into a
+// Registry, which runs them concurrently and caches the report, so frequent
+// probes from several load balancers cannot overload the database.
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Check and report statuses. A failed optional check is reported as warn,
+// which makes the report warn rather than fail.
+const (
+	StatusOK   = "ok"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+)
+
+// HealthChecker reports whether a dependency is usable; nil means healthy.
+// Check must return promptly once ctx is done.
+type HealthChecker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to a HealthChecker.
+type CheckerFunc func(ctx context.Context) error
+
+// Check calls f(ctx).
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Report is the outcome of running every check in a Registry.
+type Report struct {
+	Status    string            `json:"status"`
+	Checks    map[string]Result `json:"checks"`
+	CheckedAt time.Time         `json:"checked_at"`
+}
+
+// OK reports whether no required check failed.
+func (r Report) OK() bool {
+	return r.Status != StatusFail
+}
+
+// Result is the outcome of one check. The probes are public, so why a check
+// failed is only logged.
+type Result struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+}
+
+// Registry holds named checks and the last report.
+type Registry struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu     sync.Mutex
+	checks []check
+	cached Report // valid while CheckedAt is within cacheTTL
+
+	running sync.Mutex // one run at a time; waiters reuse its report
+}
+
+type check struct {
+	name     string
+	checker  HealthChecker
+	optional bool
+}
+
+// NewRegistry returns an empty Registry. Each check gets timeout to finish;
+// a report is reused for cacheTTL.
+func NewRegistry(timeout, cacheTTL time.Duration) *Registry {
+	return &Registry{timeout: timeout, cacheTTL: cacheTTL}
+}
+
+// Register adds a check that fails the report when it fails.
+func (r *Registry) Register(name string, c HealthChecker) {
+	r.add(check{name: name, checker: c})
+}
+
+// RegisterOptional adds a check that is reported but never fails the report,
+// for dependencies only some endpoints need.
+func (r *Registry) RegisterOptional(name string, c HealthChecker) {
+	r.add(check{name: name, checker: c, optional: true})
+}
+
+func (r *Registry) add(c check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, c)
+	r.cached = Report{} // include the new check in the next report
+}
+
+// Run returns the cached report if it is fresh, and otherwise runs every
+// check concurrently. Concurrent callers share a single run.
+func (r *Registry) Run(ctx context.Context) Report {
+	r.running.Lock()
+	defer r.running.Unlock()
+
+	r.mu.Lock()
+	if report := r.cached; !report.CheckedAt.IsZero() && time.Since(report.CheckedAt) < r.cacheTTL {
+		r.mu.Unlock()
+		return report
+	}
+	checks := r.checks
+	r.mu.Unlock()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks)), CheckedAt: time.Now()}
+	results := make([]Result, len(checks))
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.run(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	for i, c := range checks {
+		report.Checks[c.name] = results[i]
+		switch {
+		case results[i].Status == StatusFail:
+			report.Status = StatusFail
+		case results[i].Status == StatusWarn && report.Status == StatusOK:
+			report.Status = StatusWarn
+		}
+	}
+
+	r.mu.Lock()
+	if len(r.checks) == len(checks) { // else a check was added meanwhile
+		r.cached = report
+	}
+	r.mu.Unlock()
+	return report
+}
+
+// run runs one check within the registry's timeout and logs why it failed.
+// The result is shared with other callers, so it is not cut short when ctx
+// is cancelled.
+func (r *Registry) run(ctx context.Context, c check) Result {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.checker.Check(ctx)
+	result := Result{Status: StatusOK, Latency: time.Since(start).String()}
+	if err != nil {
+		result.Status = StatusFail
+		if c.optional {
+			result.Status = StatusWarn
+		}
+		slog.WarnContext(ctx, "health check failed", "check", c.name, "status", result.Status, "error", err)
+	}
+	return result
+}
@@ -0,0 +1,33 @@
+package ledger
+
+import "errors"
+
+// Sentinel errors for the ledger domain.
+var (
+	// ErrAccountNotFound is returned when an account does not exist or belongs to another user.
+	ErrAccountNotFound = errors.New("account not found")
+
+	// ErrEntryNotFound is returned when a journal entry does not exist or belongs to another user.
+	ErrEntryNotFound = errors.New("journal entry not found")
+
+	// ErrAccountNameTaken is returned when the user already has an account with the same name.
+	ErrAccountNameTaken = errors.New("an account with this name already exists")
+
+	// ErrInvalidAccountType is returned for account types outside the five standard ones.
+	ErrInvalidAccountType = errors.New("type must be one of asset, liability, income, expense, equity")
+
+	// ErrInvalidAccountName is returned when the account name is empty.
+	ErrInvalidAccountName = errors.New("name is required")
+
+	// ErrTooFewPostings is returned when a journal entry has fewer than two postings.
+	ErrTooFewPostings = errors.New("a journal entry needs at least two postings")
+
+	// ErrZeroPosting is returned when a posting amount is zero.
+	ErrZeroPosting = errors.New("posting amounts must not be zero")
+
+	// ErrCurrencyMismatch is returned when a posting's currency differs from its account's.
+	ErrCurrencyMismatch = errors.New("posting currency does not match account currency")
+
+	// ErrUnbalanced is returned when the postings of an entry do not sum to zero in every currency.
+	ErrUnbalanced = errors.New("postings must sum to zero in each currency")
+)
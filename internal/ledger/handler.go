@@ -0,0 +1,202 @@
+package ledger
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/auth"
+	jsonutil "github.com/Ajay01103/goTransactonsAPI/internal/json"
+	"github.com/Ajay01103/goTransactonsAPI/internal/money"
+)
+
+// Handler holds all HTTP handlers for the ledger domain.
+type Handler struct {
+	service Service
+}
+
+// NewHandler constructs a Handler with the given ledger Service.
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+type createAccountRequest struct {
+	Name     string      `json:"name"`
+	Type     AccountType `json:"type"`
+	Currency string      `json:"currency"`
+}
+
+type postingRequest struct {
+	AccountID string        `json:"account_id"`
+	Amount    *money.Amount `json:"amount"`
+}
+
+type createEntryRequest struct {
+	Description string           `json:"description"`
+	OccurredAt  time.Time        `json:"occurred_at"`
+	Postings    []postingRequest `json:"postings"`
+}
+
+// ListAccounts handles GET /ledger/accounts.
+func (h *Handler) ListAccounts(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.ListAccounts(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, resp)
+}
+
+// CreateAccount handles POST /ledger/accounts.
+func (h *Handler) CreateAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req createAccountRequest
+	if err := jsonutil.Read(r, &req); err != nil {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if req.Name == "" || req.Type == "" || req.Currency == "" {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "name, type and currency are required"})
+		return
+	}
+
+	resp, err := h.service.CreateAccount(r.Context(), userID, CreateAccountInput{
+		Name:     req.Name,
+		Type:     req.Type,
+		Currency: req.Currency,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	jsonutil.Write(w, http.StatusCreated, resp)
+}
+
+// GetAccount handles GET /ledger/accounts/{id}.
+func (h *Handler) GetAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.GetAccount(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, resp)
+}
+
+// ListEntries handles GET /ledger/entries.
+func (h *Handler) ListEntries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	resp, err := h.service.ListEntries(r.Context(), userID, ListEntriesInput{Limit: limit, Offset: offset})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, resp)
+}
+
+// CreateEntry handles POST /ledger/entries.
+func (h *Handler) CreateEntry(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req createEntryRequest
+	if err := jsonutil.Read(r, &req); err != nil {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	postings := make([]PostingInput, 0, len(req.Postings))
+	for _, p := range req.Postings {
+		if p.AccountID == "" || p.Amount == nil {
+			jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "every posting needs account_id and amount"})
+			return
+		}
+		postings = append(postings, PostingInput{AccountID: p.AccountID, Amount: *p.Amount})
+	}
+
+	resp, err := h.service.CreateEntry(r.Context(), userID, CreateEntryInput{
+		Description: req.Description,
+		OccurredAt:  req.OccurredAt,
+		Postings:    postings,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	jsonutil.Write(w, http.StatusCreated, resp)
+}
+
+// GetEntry handles GET /ledger/entries/{id}.
+func (h *Handler) GetEntry(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.GetEntry(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, resp)
+}
+
+// currentUserID reads the userID set by auth.RequireAuth, writing a 401 if absent.
+func currentUserID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID, ok := r.Context().Value(auth.ContextKeyUserID).(string)
+	if !ok || userID == "" {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return "", false
+	}
+	return userID, true
+}
+
+// writeError maps domain errors onto HTTP status codes.
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrAccountNotFound), errors.Is(err, ErrEntryNotFound):
+		jsonutil.Write(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrAccountNameTaken):
+		jsonutil.Write(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrInvalidAccountName), errors.Is(err, ErrInvalidAccountType),
+		errors.Is(err, ErrTooFewPostings), errors.Is(err, ErrZeroPosting),
+		errors.Is(err, money.ErrUnknownCurrency), errors.Is(err, money.ErrOverflow):
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrCurrencyMismatch), errors.Is(err, ErrUnbalanced):
+		jsonutil.Write(w, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+	default:
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+}
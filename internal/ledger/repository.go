@@ -0,0 +1,239 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql"
+	repo "github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql/sqlc"
+	"github.com/Ajay01103/goTransactonsAPI/internal/money"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type postgresRepository struct {
+	tx      *postgresql.TxManager
+	queries *repo.Queries
+}
+
+// NewPostgresRepository constructs a ledger Repository backed by sqlc-generated Queries.
+// All sqlc and pgtype details are contained within this file — nothing leaks outward.
+func NewPostgresRepository(tx *postgresql.TxManager, queries *repo.Queries) Repository {
+	return &postgresRepository{tx: tx, queries: queries}
+}
+
+func (r *postgresRepository) CreateAccount(ctx context.Context, params CreateAccountParams) (Account, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).CreateAccount(ctx, repo.CreateAccountParams{
+		ID:       params.ID,
+		UserID:   params.UserID,
+		Name:     params.Name,
+		Type:     string(params.Type),
+		Currency: params.Currency.Code,
+	})
+	if err != nil {
+		return Account{}, mapError(err)
+	}
+
+	return toAccount(row)
+}
+
+func (r *postgresRepository) GetAccount(ctx context.Context, userID, id string) (Account, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetAccount(ctx, repo.GetAccountParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Account{}, ErrAccountNotFound
+		}
+		return Account{}, err
+	}
+
+	return toAccount(row)
+}
+
+func (r *postgresRepository) ListAccounts(ctx context.Context, userID string) ([]Account, error) {
+	rows, err := postgresql.QueriesFromContext(ctx, r.queries).ListAccounts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Account, 0, len(rows))
+	for _, row := range rows {
+		a, err := toAccount(row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+
+	return out, nil
+}
+
+func (r *postgresRepository) AccountBalance(ctx context.Context, account Account) (money.Amount, error) {
+	balance, err := postgresql.QueriesFromContext(ctx, r.queries).GetAccountBalance(ctx, account.ID)
+	if err != nil {
+		return money.Amount{}, err
+	}
+
+	return money.FromDecimal(balance, account.Currency)
+}
+
+// CreateEntry inserts the journal entry and every posting in one unit of
+// work (joining the caller's transaction as a savepoint if there is one).
+// The balance constraint trigger is deferred, so an unbalanced entry is
+// rejected at COMMIT and nothing is persisted.
+func (r *postgresRepository) CreateEntry(ctx context.Context, params CreateEntryParams) (JournalEntry, error) {
+	var entry JournalEntry
+	err := r.tx.RunInTx(ctx, func(ctx context.Context, q *repo.Queries) error {
+		row, err := q.CreateJournalEntry(ctx, repo.CreateJournalEntryParams{
+			ID:          params.ID,
+			UserID:      params.UserID,
+			Description: params.Description,
+			OccurredAt:  pgtype.Timestamptz{Time: params.OccurredAt, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		postings := make([]repo.Posting, 0, len(params.Postings))
+		for i, p := range params.Postings {
+			posting, err := q.CreatePosting(ctx, repo.CreatePostingParams{
+				ID:             p.ID,
+				JournalEntryID: row.ID,
+				Line:           int32(i + 1),
+				AccountID:      p.AccountID,
+				Amount:         p.Amount.Decimal(),
+				Currency:       p.Amount.Currency().Code,
+			})
+			if err != nil {
+				return err
+			}
+			postings = append(postings, posting)
+		}
+
+		entry, err = toEntry(row, postings)
+		return err
+	})
+	if err != nil {
+		return JournalEntry{}, mapError(err)
+	}
+
+	return entry, nil
+}
+
+func (r *postgresRepository) GetEntry(ctx context.Context, userID, id string) (JournalEntry, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetJournalEntry(ctx, repo.GetJournalEntryParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return JournalEntry{}, ErrEntryNotFound
+		}
+		return JournalEntry{}, err
+	}
+
+	postings, err := postgresql.QueriesFromContext(ctx, r.queries).ListPostingsByJournalEntry(ctx, row.ID)
+	if err != nil {
+		return JournalEntry{}, err
+	}
+
+	return toEntry(row, postings)
+}
+
+func (r *postgresRepository) ListEntries(ctx context.Context, userID string, limit, offset int) ([]JournalEntry, error) {
+	rows, err := postgresql.QueriesFromContext(ctx, r.queries).ListJournalEntries(ctx, repo.ListJournalEntriesParams{
+		UserID: userID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return []JournalEntry{}, nil
+	}
+
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	postings, err := postgresql.QueriesFromContext(ctx, r.queries).ListPostingsByJournalEntries(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byEntry := make(map[string][]repo.Posting, len(rows))
+	for _, p := range postings {
+		byEntry[p.JournalEntryID] = append(byEntry[p.JournalEntryID], p)
+	}
+
+	out := make([]JournalEntry, 0, len(rows))
+	for _, row := range rows {
+		e, err := toEntry(row, byEntry[row.ID])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+
+	return out, nil
+}
+
+func toAccount(row repo.Account) (Account, error) {
+	currency, err := money.LookupCurrency(row.Currency)
+	if err != nil {
+		return Account{}, err
+	}
+
+	return Account{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		Name:      row.Name,
+		Type:      AccountType(row.Type),
+		Currency:  currency,
+		CreatedAt: row.CreatedAt.Time,
+	}, nil
+}
+
+func toEntry(row repo.JournalEntry, postings []repo.Posting) (JournalEntry, error) {
+	entry := JournalEntry{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		Description: row.Description,
+		OccurredAt:  row.OccurredAt.Time,
+		CreatedAt:   row.CreatedAt.Time,
+		Postings:    make([]Posting, 0, len(postings)),
+	}
+
+	for _, p := range postings {
+		currency, err := money.LookupCurrency(p.Currency)
+		if err != nil {
+			return JournalEntry{}, err
+		}
+		amount, err := money.FromDecimal(p.Amount, currency)
+		if err != nil {
+			return JournalEntry{}, err
+		}
+		entry.Postings = append(entry.Postings, Posting{
+			ID:        p.ID,
+			AccountID: p.AccountID,
+			Amount:    amount,
+		})
+	}
+
+	return entry, nil
+}
+
+// mapError translates constraint violations into domain sentinels.
+func mapError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case "23505": // unique_violation — accounts (user_id, name)
+		return ErrAccountNameTaken
+	case "23503": // foreign_key_violation — posting (account_id, currency) has no matching account
+		return ErrCurrencyMismatch
+	case "23514": // check_violation — raised by the deferred postings_balanced trigger
+		return ErrUnbalanced
+	}
+	return err
+}
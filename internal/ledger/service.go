@@ -0,0 +1,240 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lucsky/cuid"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/money"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 100
+)
+
+type svc struct {
+	repo Repository
+}
+
+// NewService wires a ledger Repository into a Service.
+func NewService(repo Repository) Service {
+	return &svc{repo: repo}
+}
+
+// CreateAccount opens a new account for userID.
+func (s *svc) CreateAccount(ctx context.Context, userID string, input CreateAccountInput) (AccountResponse, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return AccountResponse{}, ErrInvalidAccountName
+	}
+	if !input.Type.Valid() {
+		return AccountResponse{}, ErrInvalidAccountType
+	}
+	currency, err := money.LookupCurrency(input.Currency)
+	if err != nil {
+		return AccountResponse{}, err
+	}
+
+	account, err := s.repo.CreateAccount(ctx, CreateAccountParams{
+		ID:       cuid.New(),
+		UserID:   userID,
+		Name:     name,
+		Type:     input.Type,
+		Currency: currency,
+	})
+	if err != nil {
+		return AccountResponse{}, wrap("creating account", err)
+	}
+
+	balance := money.Zero(currency)
+	return toAccountResponse(account, &balance), nil
+}
+
+// GetAccount returns an account owned by userID together with its balance.
+func (s *svc) GetAccount(ctx context.Context, userID, id string) (AccountResponse, error) {
+	account, err := s.repo.GetAccount(ctx, userID, id)
+	if err != nil {
+		return AccountResponse{}, wrap("fetching account", err)
+	}
+
+	balance, err := s.repo.AccountBalance(ctx, account)
+	if err != nil {
+		return AccountResponse{}, fmt.Errorf("computing balance: %w", err)
+	}
+
+	return toAccountResponse(account, &balance), nil
+}
+
+// ListAccounts returns every account owned by userID.
+func (s *svc) ListAccounts(ctx context.Context, userID string) ([]AccountResponse, error) {
+	accounts, err := s.repo.ListAccounts(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing accounts: %w", err)
+	}
+
+	out := make([]AccountResponse, 0, len(accounts))
+	for _, a := range accounts {
+		out = append(out, toAccountResponse(a, nil))
+	}
+	return out, nil
+}
+
+// CreateEntry validates that the postings reference the caller's accounts in
+// the right currencies and sum to zero per currency, then persists the entry
+// and its postings atomically.
+func (s *svc) CreateEntry(ctx context.Context, userID string, input CreateEntryInput) (EntryResponse, error) {
+	if len(input.Postings) < 2 {
+		return EntryResponse{}, ErrTooFewPostings
+	}
+
+	accounts := make(map[string]Account)
+	sums := make(map[string]money.Amount)
+	postings := make([]Posting, 0, len(input.Postings))
+
+	for _, p := range input.Postings {
+		if p.Amount.IsZero() {
+			return EntryResponse{}, ErrZeroPosting
+		}
+
+		account, ok := accounts[p.AccountID]
+		if !ok {
+			var err error
+			account, err = s.repo.GetAccount(ctx, userID, p.AccountID)
+			if err != nil {
+				return EntryResponse{}, wrap("fetching account", err)
+			}
+			accounts[p.AccountID] = account
+		}
+		if p.Amount.Currency().Code != account.Currency.Code {
+			return EntryResponse{}, fmt.Errorf("%w: account %s is in %s", ErrCurrencyMismatch, account.ID, account.Currency)
+		}
+
+		code := p.Amount.Currency().Code
+		sum, ok := sums[code]
+		if !ok {
+			sum = money.Zero(p.Amount.Currency())
+		}
+		sum, err := sum.Add(p.Amount)
+		if err != nil {
+			return EntryResponse{}, err
+		}
+		sums[code] = sum
+
+		postings = append(postings, Posting{
+			ID:        cuid.New(),
+			AccountID: p.AccountID,
+			Amount:    p.Amount,
+		})
+	}
+
+	for code, sum := range sums {
+		if !sum.IsZero() {
+			return EntryResponse{}, fmt.Errorf("%w: %s is off by %s", ErrUnbalanced, code, sum)
+		}
+	}
+
+	occurredAt := input.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+
+	entry, err := s.repo.CreateEntry(ctx, CreateEntryParams{
+		ID:          cuid.New(),
+		UserID:      userID,
+		Description: strings.TrimSpace(input.Description),
+		OccurredAt:  occurredAt,
+		Postings:    postings,
+	})
+	if err != nil {
+		return EntryResponse{}, wrap("creating journal entry", err)
+	}
+
+	return toEntryResponse(entry), nil
+}
+
+// GetEntry returns a journal entry owned by userID with its postings.
+func (s *svc) GetEntry(ctx context.Context, userID, id string) (EntryResponse, error) {
+	entry, err := s.repo.GetEntry(ctx, userID, id)
+	if err != nil {
+		return EntryResponse{}, wrap("fetching journal entry", err)
+	}
+
+	return toEntryResponse(entry), nil
+}
+
+// ListEntries returns a page of the user's journal entries, newest first.
+func (s *svc) ListEntries(ctx context.Context, userID string, input ListEntriesInput) (EntryListResponse, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	offset := max(input.Offset, 0)
+
+	entries, err := s.repo.ListEntries(ctx, userID, limit, offset)
+	if err != nil {
+		return EntryListResponse{}, fmt.Errorf("listing journal entries: %w", err)
+	}
+
+	resp := EntryListResponse{
+		Entries: make([]EntryResponse, 0, len(entries)),
+		Limit:   limit,
+		Offset:  offset,
+	}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, toEntryResponse(e))
+	}
+
+	return resp, nil
+}
+
+// sentinels are returned unwrapped so handlers can map them to status codes.
+var sentinels = []error{
+	ErrAccountNotFound, ErrEntryNotFound, ErrAccountNameTaken,
+	ErrCurrencyMismatch, ErrUnbalanced,
+}
+
+func wrap(op string, err error) error {
+	for _, sentinel := range sentinels {
+		if errors.Is(err, sentinel) {
+			return err
+		}
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+func toAccountResponse(a Account, balance *money.Amount) AccountResponse {
+	return AccountResponse{
+		ID:        a.ID,
+		Name:      a.Name,
+		Type:      a.Type,
+		Currency:  a.Currency.Code,
+		Balance:   balance,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+func toEntryResponse(e JournalEntry) EntryResponse {
+	resp := EntryResponse{
+		ID:          e.ID,
+		Description: e.Description,
+		OccurredAt:  e.OccurredAt,
+		CreatedAt:   e.CreatedAt,
+		Postings:    make([]PostingResponse, 0, len(e.Postings)),
+	}
+	for _, p := range e.Postings {
+		resp.Postings = append(resp.Postings, PostingResponse{
+			ID:        p.ID,
+			AccountID: p.AccountID,
+			Amount:    p.Amount,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,167 @@
+package ledger
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/money"
+)
+
+// ── Domain model ──────────────────────────────────────────────────────────────
+
+// AccountType is one of the five standard double-entry account classes.
+type AccountType string
+
+const (
+	AccountAsset     AccountType = "asset"
+	AccountLiability AccountType = "liability"
+	AccountIncome    AccountType = "income"
+	AccountExpense   AccountType = "expense"
+	AccountEquity    AccountType = "equity"
+)
+
+// Valid reports whether t is one of the known account types.
+func (t AccountType) Valid() bool {
+	switch t {
+	case AccountAsset, AccountLiability, AccountIncome, AccountExpense, AccountEquity:
+		return true
+	}
+	return false
+}
+
+// Account is a bucket that postings debit or credit, e.g. "Checking" or "Visa".
+type Account struct {
+	ID        string
+	UserID    string
+	Name      string
+	Type      AccountType
+	Currency  money.Currency
+	CreatedAt time.Time
+}
+
+// Posting is one line of a journal entry. A positive amount debits the
+// account, a negative amount credits it.
+type Posting struct {
+	ID        string
+	AccountID string
+	Amount    money.Amount
+}
+
+// JournalEntry is a set of postings that together sum to zero per currency.
+type JournalEntry struct {
+	ID          string
+	UserID      string
+	Description string
+	OccurredAt  time.Time
+	CreatedAt   time.Time
+	Postings    []Posting
+}
+
+// ── Service DTOs ──────────────────────────────────────────────────────────────
+
+// CreateAccountInput is the DTO passed from handler → service when opening an account.
+type CreateAccountInput struct {
+	Name     string
+	Type     AccountType
+	Currency string
+}
+
+// PostingInput is one line of a journal entry as submitted by the client.
+type PostingInput struct {
+	AccountID string
+	Amount    money.Amount
+}
+
+// CreateEntryInput is the DTO passed from handler → service when recording a journal entry.
+type CreateEntryInput struct {
+	Description string
+	OccurredAt  time.Time // zero value means "now"
+	Postings    []PostingInput
+}
+
+// ListEntriesInput carries pagination for listing journal entries.
+type ListEntriesInput struct {
+	Limit  int
+	Offset int
+}
+
+// AccountResponse is the public account DTO.
+type AccountResponse struct {
+	ID        string        `json:"id"`
+	Name      string        `json:"name"`
+	Type      AccountType   `json:"type"`
+	Currency  string        `json:"currency"`
+	Balance   *money.Amount `json:"balance,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// PostingResponse is the public posting DTO.
+type PostingResponse struct {
+	ID        string       `json:"id"`
+	AccountID string       `json:"account_id"`
+	Amount    money.Amount `json:"amount"`
+}
+
+// EntryResponse is the public journal entry DTO.
+type EntryResponse struct {
+	ID          string            `json:"id"`
+	Description string            `json:"description"`
+	OccurredAt  time.Time         `json:"occurred_at"`
+	CreatedAt   time.Time         `json:"created_at"`
+	Postings    []PostingResponse `json:"postings"`
+}
+
+// EntryListResponse wraps a page of journal entries.
+type EntryListResponse struct {
+	Entries []EntryResponse `json:"entries"`
+	Limit   int             `json:"limit"`
+	Offset  int             `json:"offset"`
+}
+
+// ── Repository DTOs ───────────────────────────────────────────────────────────
+
+// CreateAccountParams carries the data needed to persist a new account.
+type CreateAccountParams struct {
+	ID       string
+	UserID   string
+	Name     string
+	Type     AccountType
+	Currency money.Currency
+}
+
+// CreateEntryParams carries a journal entry and all its postings. The
+// repository persists them atomically.
+type CreateEntryParams struct {
+	ID          string
+	UserID      string
+	Description string
+	OccurredAt  time.Time
+	Postings    []Posting
+}
+
+// ── Contracts ─────────────────────────────────────────────────────────────────
+
+// Repository defines the data-access contract for the ledger domain.
+// Every lookup is scoped to a user ID.
+type Repository interface {
+	CreateAccount(ctx context.Context, params CreateAccountParams) (Account, error)
+	GetAccount(ctx context.Context, userID, id string) (Account, error)
+	ListAccounts(ctx context.Context, userID string) ([]Account, error)
+	AccountBalance(ctx context.Context, account Account) (money.Amount, error)
+
+	// CreateEntry inserts the entry and its postings in a single database transaction.
+	CreateEntry(ctx context.Context, params CreateEntryParams) (JournalEntry, error)
+	GetEntry(ctx context.Context, userID, id string) (JournalEntry, error)
+	ListEntries(ctx context.Context, userID string, limit, offset int) ([]JournalEntry, error)
+}
+
+// Service defines the business-logic contract for the ledger domain.
+type Service interface {
+	CreateAccount(ctx context.Context, userID string, input CreateAccountInput) (AccountResponse, error)
+	GetAccount(ctx context.Context, userID, id string) (AccountResponse, error)
+	ListAccounts(ctx context.Context, userID string) ([]AccountResponse, error)
+
+	CreateEntry(ctx context.Context, userID string, input CreateEntryInput) (EntryResponse, error)
+	GetEntry(ctx context.Context, userID, id string) (EntryResponse, error)
+	ListEntries(ctx context.Context, userID string, input ListEntriesInput) (EntryListResponse, error)
+}
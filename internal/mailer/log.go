@@ -0,0 +1,22 @@
+package mailer
+
+import (
+	"context"
+	"log/slog"
+)
+
+type logMailer struct {
+	logger *slog.Logger
+}
+
+// NewLogMailer returns a Mailer that only logs each message, body included.
+// It is meant for local development; never use it in production, since reset
+// links end up in the logs.
+func NewLogMailer(logger *slog.Logger) Mailer {
+	return &logMailer{logger: logger}
+}
+
+func (m *logMailer) Send(ctx context.Context, msg Message) error {
+	m.logger.InfoContext(ctx, "email (not sent)", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
@@ -0,0 +1,18 @@
+// Package mailer sends transactional email (password resets, verification
+// links) through a pluggable Mailer.
+package mailer
+
+import "context"
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers a Message. Implementations must be safe for concurrent use.
+// Those that talk to a server also implement health.HealthChecker.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// otherQuery labels queries that are not from sqlc, such as BEGIN and COMMIT
+// or migrations, which would otherwise be labelled by their whole SQL text.
+const otherQuery = "other"
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	name string
+	at   time.Time
+}
+
+// TraceQueryStart implements pgx.QueryTracer; set Metrics as the pool's
+// tracer (postgresql.PoolConfig.Tracer) to time every query.
+func (m *Metrics) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{name: queryName(data.SQL), at: time.Now()})
+}
+
+// TraceQueryEnd implements pgx.QueryTracer.
+func (m *Metrics) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+
+	outcome := "ok"
+	switch {
+	case data.Err == nil, errors.Is(data.Err, pgx.ErrNoRows):
+	case errors.Is(data.Err, context.Canceled), errors.Is(data.Err, context.DeadlineExceeded):
+		outcome = "canceled"
+	default:
+		outcome = "error"
+	}
+	m.queries.WithLabelValues(start.name, outcome).Observe(time.Since(start.at).Seconds())
+}
+
+// queryName returns the name sqlc puts at the top of each generated query,
+// "-- name: GetUser :one", or otherQuery.
+func queryName(sql string) string {
+	rest, ok := strings.CutPrefix(sql, "-- name: ")
+	if !ok {
+		return otherQuery
+	}
+	name, _, ok := strings.Cut(rest, " ")
+	if !ok || name == "" {
+		return otherQuery
+	}
+	return name
+}
+
+// RegisterPool exports the pool's connection counts, acquire waits and
+// connection churn, read from pgxpool.Stat on each scrape.
+func (m *Metrics) RegisterPool(pool *pgxpool.Pool) {
+	m.registry.MustRegister(poolCollector{pool: pool})
+}
+
+var (
+	poolConns = prometheus.NewDesc(namespace+"_db_pool_connections",
+		"Connections in the pool, by state.", []string{"state"}, nil)
+	poolMaxConns = prometheus.NewDesc(namespace+"_db_pool_max_connections",
+		"Most connections the pool will open.", nil, nil)
+	poolAcquires = prometheus.NewDesc(namespace+"_db_pool_acquires_total",
+		"Connections acquired from the pool.", nil, nil)
+	poolAcquireSeconds = prometheus.NewDesc(namespace+"_db_pool_acquire_seconds_total",
+		"Time spent acquiring connections.", nil, nil)
+	poolWaits = prometheus.NewDesc(namespace+"_db_pool_waits_total",
+		"Acquires that waited because every connection was busy.", nil, nil)
+	poolWaitSeconds = prometheus.NewDesc(namespace+"_db_pool_wait_seconds_total",
+		"Time spent waiting for a busy pool.", nil, nil)
+	poolCanceledAcquires = prometheus.NewDesc(namespace+"_db_pool_canceled_acquires_total",
+		"Acquires canceled by their context.", nil, nil)
+	poolNewConns = prometheus.NewDesc(namespace+"_db_pool_new_connections_total",
+		"Connections opened.", nil, nil)
+	poolDestroyedConns = prometheus.NewDesc(namespace+"_db_pool_destroyed_connections_total",
+		"Connections closed for exceeding their lifetime or idle time, by reason.", []string{"reason"}, nil)
+)
+
+// poolCollector reads pgxpool.Stat at scrape time.
+type poolCollector struct {
+	pool *pgxpool.Pool
+}
+
+func (c poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		poolConns, poolMaxConns, poolAcquires, poolAcquireSeconds, poolWaits,
+		poolWaitSeconds, poolCanceledAcquires, poolNewConns, poolDestroyedConns,
+	} {
+		ch <- d
+	}
+}
+
+func (c poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+	gauge, counter := prometheus.GaugeValue, prometheus.CounterValue
+
+	ch <- prometheus.MustNewConstMetric(poolConns, gauge, float64(s.AcquiredConns()), "acquired")
+	ch <- prometheus.MustNewConstMetric(poolConns, gauge, float64(s.IdleConns()), "idle")
+	ch <- prometheus.MustNewConstMetric(poolConns, gauge, float64(s.ConstructingConns()), "constructing")
+	ch <- prometheus.MustNewConstMetric(poolMaxConns, gauge, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquires, counter, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireSeconds, counter, s.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(poolWaits, counter, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolWaitSeconds, counter, s.EmptyAcquireWaitTime().Seconds())
+	ch <- prometheus.MustNewConstMetric(poolCanceledAcquires, counter, float64(s.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolNewConns, counter, float64(s.NewConnsCount()))
+	ch <- prometheus.MustNewConstMetric(poolDestroyedConns, counter, float64(s.MaxLifetimeDestroyCount()), "lifetime")
+	ch <- prometheus.MustNewConstMetric(poolDestroyedConns, counter, float64(s.MaxIdleDestroyCount()), "idle")
+}
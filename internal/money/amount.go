@@ -0,0 +1,238 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Amount is an exact monetary value: a signed count of minor units (cents,
+// fils, yen, …) in a single ISO 4217 currency. Amounts are immutable and
+// never represented as floating point.
+type Amount struct {
+	minor    int64
+	currency Currency
+}
+
+// New returns an Amount of minor units in currency c.
+func New(minor int64, c Currency) Amount {
+	return Amount{minor: minor, currency: c}
+}
+
+// Zero returns a zero Amount in currency c.
+func Zero(c Currency) Amount {
+	return Amount{currency: c}
+}
+
+// Parse reads a decimal string such as "12.34" in currency c. Trailing zeros
+// beyond the currency's exponent are accepted ("12.3400" USD), but any
+// non-zero excess digit is rejected with ErrExcessPrecision.
+func Parse(s string, c Currency) (Amount, error) {
+	neg, intPart, fracPart, err := splitDecimal(strings.TrimSpace(s))
+	if err != nil {
+		return Amount{}, err
+	}
+
+	if len(fracPart) > c.Exponent {
+		if strings.TrimRight(fracPart[c.Exponent:], "0") != "" {
+			return Amount{}, fmt.Errorf("%w: %q has more than %d decimal places for %s", ErrExcessPrecision, s, c.Exponent, c.Code)
+		}
+		fracPart = fracPart[:c.Exponent]
+	}
+	fracPart += strings.Repeat("0", c.Exponent-len(fracPart))
+
+	digits := intPart + fracPart
+	if neg {
+		digits = "-" + digits
+	}
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return Amount{}, fmt.Errorf("%w: %q", ErrOverflow, s)
+		}
+		return Amount{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	return Amount{minor: minor, currency: c}, nil
+}
+
+// FromDecimal combines a NUMERIC value read from the database with its currency.
+func FromDecimal(d Decimal, c Currency) (Amount, error) {
+	if !d.Valid() {
+		return Amount{}, fmt.Errorf("%w: NULL", ErrInvalidAmount)
+	}
+	return Parse(d.String(), c)
+}
+
+// MinorUnits returns the amount as an integer count of minor units.
+func (a Amount) MinorUnits() int64 {
+	return a.minor
+}
+
+// Currency returns the amount's currency.
+func (a Amount) Currency() Currency {
+	return a.currency
+}
+
+// IsZero reports whether the amount is exactly zero.
+func (a Amount) IsZero() bool { return a.minor == 0 }
+
+// IsPositive reports whether the amount is greater than zero.
+func (a Amount) IsPositive() bool { return a.minor > 0 }
+
+// IsNegative reports whether the amount is less than zero.
+func (a Amount) IsNegative() bool { return a.minor < 0 }
+
+// String formats the amount as a decimal with exactly Exponent fractional
+// digits, e.g. "12.30" for USD, "1230" for JPY, "-0.005" for KWD.
+func (a Amount) String() string {
+	sign := ""
+	abs := uint64(a.minor)
+	if a.minor < 0 {
+		sign = "-"
+		abs = uint64(-(a.minor + 1)) + 1 // safe for math.MinInt64
+	}
+
+	digits := strconv.FormatUint(abs, 10)
+	exp := a.currency.Exponent
+	if exp == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
+
+// Decimal returns the amount as a Decimal suitable for a NUMERIC column.
+func (a Amount) Decimal() Decimal {
+	return Decimal{value: a.String(), valid: true}
+}
+
+// Add returns a + b. Both amounts must share a currency.
+func (a Amount) Add(b Amount) (Amount, error) {
+	if err := a.sameCurrency(b); err != nil {
+		return Amount{}, err
+	}
+	sum := a.minor + b.minor
+	if (b.minor > 0 && sum < a.minor) || (b.minor < 0 && sum > a.minor) {
+		return Amount{}, ErrOverflow
+	}
+	return Amount{minor: sum, currency: a.currency}, nil
+}
+
+// Sub returns a - b. Both amounts must share a currency.
+func (a Amount) Sub(b Amount) (Amount, error) {
+	if b.minor == math.MinInt64 {
+		return Amount{}, ErrOverflow
+	}
+	return a.Add(Amount{minor: -b.minor, currency: b.currency})
+}
+
+// Cmp compares a and b, returning -1, 0 or +1. Both amounts must share a currency.
+func (a Amount) Cmp(b Amount) (int, error) {
+	if err := a.sameCurrency(b); err != nil {
+		return 0, err
+	}
+	switch {
+	case a.minor < b.minor:
+		return -1, nil
+	case a.minor > b.minor:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Allocate splits the amount into len(ratios) parts proportional to ratios
+// without losing a single minor unit: the remainder left by integer division
+// is handed out one minor unit at a time, starting with the first part.
+// Allocating 100 cents by (1, 1, 1) yields 34, 33, 33.
+func (a Amount) Allocate(ratios ...int) ([]Amount, error) {
+	if len(ratios) == 0 {
+		return nil, errors.New("money: allocate needs at least one ratio")
+	}
+
+	total := int64(0)
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, errors.New("money: allocation ratios must not be negative")
+		}
+		total += int64(r)
+	}
+	if total == 0 {
+		return nil, errors.New("money: allocation ratios must not all be zero")
+	}
+
+	parts := make([]Amount, len(ratios))
+	remainder := a.minor
+	whole := big.NewInt(a.minor)
+	for i, r := range ratios {
+		share := new(big.Int).Mul(whole, big.NewInt(int64(r)))
+		share.Quo(share, big.NewInt(total)) // truncates toward zero
+		parts[i] = Amount{minor: share.Int64(), currency: a.currency}
+		remainder -= parts[i].minor
+	}
+
+	step := int64(1)
+	if remainder < 0 {
+		step = -1
+	}
+	for i := 0; remainder != 0; i = (i + 1) % len(parts) {
+		if ratios[i] == 0 {
+			continue
+		}
+		parts[i].minor += step
+		remainder -= step
+	}
+
+	return parts, nil
+}
+
+func (a Amount) sameCurrency(b Amount) error {
+	if a.currency.Code != b.currency.Code {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, a.currency.Code, b.currency.Code)
+	}
+	return nil
+}
+
+// amountJSON is the wire format: the value is a decimal string so clients
+// never round-trip money through a float.
+type amountJSON struct {
+	Value    string `json:"value"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON encodes the amount as {"value":"12.34","currency":"USD"}.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	if a.currency.Code == "" {
+		return []byte("null"), nil
+	}
+	return json.Marshal(amountJSON{Value: a.String(), Currency: a.currency.Code})
+}
+
+// UnmarshalJSON decodes {"value":"12.34","currency":"USD"}, rejecting unknown
+// currencies and excess precision.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	var raw amountJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("%w: amount must be an object with string value and currency", ErrInvalidAmount)
+	}
+
+	c, err := LookupCurrency(raw.Currency)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := Parse(raw.Value, c)
+	if err != nil {
+		return err
+	}
+
+	*a = parsed
+	return nil
+}
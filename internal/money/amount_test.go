@@ -0,0 +1,216 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+var (
+	jpy = MustCurrency("JPY")
+	usd = MustCurrency("USD")
+	kwd = MustCurrency("KWD")
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in       string
+		currency Currency
+		minor    int64
+		err      error
+	}{
+		{"1230", jpy, 1230, nil},
+		{"0", jpy, 0, nil},
+		{"12.0", jpy, 12, nil},
+		{"12.5", jpy, 0, ErrExcessPrecision},
+
+		{"12.34", usd, 1234, nil},
+		{"12.3", usd, 1230, nil},
+		{"12", usd, 1200, nil},
+		{"12.3400", usd, 1234, nil},
+		{" 7.01 ", usd, 701, nil},
+		{"+7.01", usd, 701, nil},
+		{"-0.05", usd, -5, nil},
+		{"-12.34", usd, -1234, nil},
+		{"1.005", usd, 0, ErrExcessPrecision},
+		{"0.001", usd, 0, ErrExcessPrecision},
+
+		{"1.234", kwd, 1234, nil},
+		{"-0.005", kwd, -5, nil},
+		{"0.5", kwd, 500, nil},
+		{"1.2345", kwd, 0, ErrExcessPrecision},
+
+		{"92233720368547758.07", usd, math.MaxInt64, nil},
+		{"-92233720368547758.08", usd, math.MinInt64, nil},
+		{"92233720368547758.08", usd, 0, ErrOverflow},
+		{"99999999999999999999", jpy, 0, ErrOverflow},
+
+		{"", usd, 0, ErrInvalidAmount},
+		{"abc", usd, 0, ErrInvalidAmount},
+		{"1.", usd, 0, ErrInvalidAmount},
+		{".5", usd, 0, ErrInvalidAmount},
+		{"1e3", usd, 0, ErrInvalidAmount},
+		{"1,000.00", usd, 0, ErrInvalidAmount},
+		{"--1", usd, 0, ErrInvalidAmount},
+		{"NaN", usd, 0, ErrInvalidAmount},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, tt.currency)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Parse(%q, %s) error = %v, want %v", tt.in, tt.currency.Code, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q, %s) error = %v", tt.in, tt.currency.Code, err)
+			continue
+		}
+		if got.MinorUnits() != tt.minor || got.Currency() != tt.currency {
+			t.Errorf("Parse(%q, %s) = %d %s, want %d %s", tt.in, tt.currency.Code, got.MinorUnits(), got.Currency().Code, tt.minor, tt.currency.Code)
+		}
+	}
+}
+
+func TestAmountString(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		want   string
+	}{
+		{New(1230, jpy), "1230"},
+		{New(-5, jpy), "-5"},
+		{New(0, jpy), "0"},
+		{New(1230, usd), "12.30"},
+		{New(5, usd), "0.05"},
+		{New(-5, usd), "-0.05"},
+		{New(0, usd), "0.00"},
+		{New(-5, kwd), "-0.005"},
+		{New(1234, kwd), "1.234"},
+		{New(math.MaxInt64, usd), "92233720368547758.07"},
+		{New(math.MinInt64, usd), "-92233720368547758.08"},
+	}
+	for _, tt := range tests {
+		got := tt.amount.String()
+		if got != tt.want {
+			t.Errorf("String() of %d %s = %q, want %q", tt.amount.MinorUnits(), tt.amount.Currency().Code, got, tt.want)
+			continue
+		}
+		back, err := Parse(got, tt.amount.Currency())
+		if err != nil || back != tt.amount {
+			t.Errorf("Parse(String()) of %d %s = %v, %v; want the same amount", tt.amount.MinorUnits(), tt.amount.Currency().Code, back, err)
+		}
+	}
+}
+
+func TestAddSub(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     Amount
+		sum, dif int64
+		sumErr   error
+		difErr   error
+	}{
+		{"positive", New(150, usd), New(25, usd), 175, 125, nil, nil},
+		{"negatives", New(-150, usd), New(-25, usd), -175, -125, nil, nil},
+		{"mixed signs", New(100, kwd), New(-250, kwd), -150, 350, nil, nil},
+		{"zero", New(0, jpy), New(0, jpy), 0, 0, nil, nil},
+		{"max plus one", New(math.MaxInt64, usd), New(1, usd), 0, math.MaxInt64 - 1, ErrOverflow, nil},
+		{"min minus one", New(math.MinInt64, usd), New(-1, usd), 0, math.MinInt64 + 1, ErrOverflow, nil},
+		{"max minus negative", New(math.MaxInt64, usd), New(-1, usd), math.MaxInt64 - 1, 0, nil, ErrOverflow},
+		{"minus min", New(0, usd), New(math.MinInt64, usd), math.MinInt64, 0, nil, ErrOverflow},
+		{"currency mismatch", New(100, usd), New(100, kwd), 0, 0, ErrCurrencyMismatch, ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum, err := tt.a.Add(tt.b)
+			if !errors.Is(err, tt.sumErr) {
+				t.Errorf("Add error = %v, want %v", err, tt.sumErr)
+			} else if err == nil && (sum.MinorUnits() != tt.sum || sum.Currency() != tt.a.Currency()) {
+				t.Errorf("Add = %d %s, want %d %s", sum.MinorUnits(), sum.Currency().Code, tt.sum, tt.a.Currency().Code)
+			}
+
+			dif, err := tt.a.Sub(tt.b)
+			if !errors.Is(err, tt.difErr) {
+				t.Errorf("Sub error = %v, want %v", err, tt.difErr)
+			} else if err == nil && dif.MinorUnits() != tt.dif {
+				t.Errorf("Sub = %d, want %d", dif.MinorUnits(), tt.dif)
+			}
+		})
+	}
+}
+
+func TestCmp(t *testing.T) {
+	tests := []struct {
+		a, b Amount
+		want int
+		err  error
+	}{
+		{New(1, usd), New(2, usd), -1, nil},
+		{New(2, usd), New(2, usd), 0, nil},
+		{New(-1, usd), New(-2, usd), 1, nil},
+		{New(1, usd), New(1, jpy), 0, ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		got, err := tt.a.Cmp(tt.b)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("Cmp(%s %s, %s %s) = %d, %v; want %d, %v", tt.a, tt.a.Currency().Code, tt.b, tt.b.Currency().Code, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Amount
+		ratios  []int
+		want    []int64
+		wantErr bool
+	}{
+		{"even thirds", New(100, usd), []int{1, 1, 1}, []int64{34, 33, 33}, false},
+		{"exact split", New(90, usd), []int{1, 1, 1}, []int64{30, 30, 30}, false},
+		{"two remainders", New(101, usd), []int{1, 1, 1}, []int64{34, 34, 33}, false},
+		{"weighted", New(100, usd), []int{70, 20, 10}, []int64{70, 20, 10}, false},
+		{"weighted remainder", New(5, jpy), []int{3, 7}, []int64{2, 3}, false},
+		{"skips zero ratios", New(100, usd), []int{0, 1, 1, 1}, []int64{0, 34, 33, 33}, false},
+		{"negative amount", New(-100, usd), []int{1, 1, 1}, []int64{-34, -33, -33}, false},
+		{"fewer units than parts", New(2, kwd), []int{1, 1, 1}, []int64{1, 1, 0}, false},
+		{"zero", New(0, usd), []int{1, 2}, []int64{0, 0}, false},
+		{"single part", New(math.MaxInt64, usd), []int{3}, []int64{math.MaxInt64}, false},
+		{"no ratios", New(100, usd), nil, nil, true},
+		{"negative ratio", New(100, usd), []int{1, -1}, nil, true},
+		{"all zero", New(100, usd), []int{0, 0}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := tt.amount.Allocate(tt.ratios...)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Allocate(%v) = %v, want an error", tt.ratios, parts)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Allocate(%v) error = %v", tt.ratios, err)
+			}
+
+			got := make([]int64, len(parts))
+			total := Zero(tt.amount.Currency())
+			for i, p := range parts {
+				got[i] = p.MinorUnits()
+				if p.Currency() != tt.amount.Currency() {
+					t.Errorf("part %d currency = %s, want %s", i, p.Currency().Code, tt.amount.Currency().Code)
+				}
+				if total, err = total.Add(p); err != nil {
+					t.Fatalf("summing parts: %v", err)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Allocate(%v) = %v, want %v", tt.ratios, got, tt.want)
+			}
+			if total != tt.amount {
+				t.Errorf("parts sum to %s, want %s", total, tt.amount)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// Decimal is an exact base-10 number as stored in a Postgres NUMERIC column.
+// It is the type sqlc maps NUMERIC onto, so repositories never see
+// pgtype.Numeric and values never pass through float64. A Decimal carries no
+// currency; combine it with one via FromDecimal.
+//
+// The zero value is SQL NULL.
+type Decimal struct {
+	value string
+	valid bool
+}
+
+// NewDecimal validates s as a plain decimal number ("-12.3400", "7").
+func NewDecimal(s string) (Decimal, error) {
+	if _, _, _, err := splitDecimal(s); err != nil {
+		return Decimal{}, err
+	}
+	return Decimal{value: s, valid: true}, nil
+}
+
+// Valid reports whether d holds a value (false means SQL NULL).
+func (d Decimal) Valid() bool {
+	return d.valid
+}
+
+// String returns the decimal text, or "" for NULL.
+func (d Decimal) String() string {
+	return d.value
+}
+
+// Scan implements sql.Scanner. pgx hands NUMERIC values over as text.
+func (d *Decimal) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Decimal{}
+		return nil
+	case string:
+		dec, err := NewDecimal(v)
+		if err != nil {
+			return err
+		}
+		*d = dec
+		return nil
+	case []byte:
+		return d.Scan(string(v))
+	default:
+		return fmt.Errorf("money: cannot scan %T into Decimal", src)
+	}
+}
+
+// Value implements driver.Valuer. Postgres parses the text form into NUMERIC exactly.
+func (d Decimal) Value() (driver.Value, error) {
+	if !d.valid {
+		return nil, nil
+	}
+	return d.value, nil
+}
+
+// splitDecimal breaks s into sign, integer digits and fractional digits,
+// rejecting anything that is not a plain decimal (no exponents, NaN or Infinity).
+func splitDecimal(s string) (neg bool, intPart, fracPart string, err error) {
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg, s = true, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	intPart, fracPart, hasDot := strings.Cut(s, ".")
+	if intPart == "" || (hasDot && fracPart == "") || !allDigits(intPart) || !allDigits(fracPart) {
+		return false, "", "", fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	return neg, intPart, fracPart, nil
+}
+
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecimalScanValue(t *testing.T) {
+	tests := []struct {
+		src     any
+		valid   bool
+		want    string
+		wantErr bool
+	}{
+		{"12.3400", true, "12.3400", false},
+		{"-0.005", true, "-0.005", false},
+		{"7", true, "7", false},
+		{[]byte("1234.56"), true, "1234.56", false},
+		{nil, false, "", false},
+		{"1e3", false, "", true},
+		{"NaN", false, "", true},
+		{"Infinity", false, "", true},
+		{12.5, false, "", true}, // never through a float
+	}
+	for _, tt := range tests {
+		var d Decimal
+		err := d.Scan(tt.src)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Scan(%v) = %q, want an error", tt.src, d.String())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Scan(%v) error = %v", tt.src, err)
+			continue
+		}
+		if d.Valid() != tt.valid || d.String() != tt.want {
+			t.Errorf("Scan(%v) = %q (valid %v), want %q (valid %v)", tt.src, d.String(), d.Valid(), tt.want, tt.valid)
+		}
+
+		v, err := d.Value()
+		if err != nil {
+			t.Errorf("Value() of %v error = %v", tt.src, err)
+			continue
+		}
+		if !tt.valid {
+			if v != nil {
+				t.Errorf("Value() of NULL = %v, want nil", v)
+			}
+			continue
+		}
+
+		var back Decimal
+		if err := back.Scan(v); err != nil || back != d {
+			t.Errorf("Scan(Value()) of %v = %q, %v; want %q", tt.src, back.String(), err, d.String())
+		}
+	}
+}
+
+func TestDecimalAmountRoundTrip(t *testing.T) {
+	for _, a := range []Amount{New(1234, usd), New(-5, kwd), New(1230, jpy), New(0, usd)} {
+		v, err := a.Decimal().Value()
+		if err != nil {
+			t.Fatalf("Value() error = %v", err)
+		}
+
+		var d Decimal
+		if err := d.Scan(v); err != nil {
+			t.Fatalf("Scan(%v) error = %v", v, err)
+		}
+		back, err := FromDecimal(d, a.Currency())
+		if err != nil || back != a {
+			t.Errorf("round trip of %s %s = %v, %v", a, a.Currency().Code, back, err)
+		}
+	}
+
+	if _, err := FromDecimal(Decimal{}, usd); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("FromDecimal(NULL) error = %v, want %v", err, ErrInvalidAmount)
+	}
+	// a NUMERIC with more places than the currency allows must not be truncated
+	if _, err := FromDecimal(Decimal{value: "1.005", valid: true}, usd); !errors.Is(err, ErrExcessPrecision) {
+		t.Errorf("FromDecimal(1.005 USD) error = %v, want %v", err, ErrExcessPrecision)
+	}
+}
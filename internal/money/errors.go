@@ -0,0 +1,22 @@
+package money
+
+import "errors"
+
+// Sentinel errors for the money package.
+var (
+	// ErrUnknownCurrency is returned for codes that are not in the ISO 4217 table.
+	ErrUnknownCurrency = errors.New("unknown ISO 4217 currency code")
+
+	// ErrInvalidAmount is returned when a string is not a plain decimal number.
+	ErrInvalidAmount = errors.New("invalid decimal amount")
+
+	// ErrExcessPrecision is returned when an amount has more fractional digits
+	// than its currency allows (e.g. "1.005" USD or "1.5" JPY).
+	ErrExcessPrecision = errors.New("amount has more decimal places than the currency allows")
+
+	// ErrCurrencyMismatch is returned when combining amounts in different currencies.
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+
+	// ErrOverflow is returned when an operation exceeds the int64 range of minor units.
+	ErrOverflow = errors.New("amount overflows")
+)
@@ -0,0 +1,36 @@
+package privacy
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/mailer"
+)
+
+// withToken appends ?token=raw (or &token=raw) to base.
+func withToken(base, raw string) string {
+	sep := "?"
+	if strings.Contains(base, "?") {
+		sep = "&"
+	}
+	return base + sep + "token=" + url.QueryEscape(raw)
+}
+
+func exportReadyEmail(profile Profile, link string, expiresAt time.Time) mailer.Message {
+	return mailer.Message{
+		To:      profile.Email,
+		Subject: "Your data export is ready",
+		Body: fmt.Sprintf(`Hi %s,
+
+The copy of your data you asked for is ready. Open the link below to download
+it as a ZIP archive. The link works until %s; after that
+the archive is deleted and you can request a new one.
+
+%s
+
+Anyone with the link can download your data, so do not share it.
+`, profile.Name, expiresAt.UTC().Format("2 January 2006 15:04 MST"), link),
+	}
+}
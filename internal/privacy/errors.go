@@ -0,0 +1,17 @@
+package privacy
+
+import "errors"
+
+// Sentinel errors for the privacy domain.
+var (
+	// ErrExportNotFound is returned by the repository when no export matches.
+	ErrExportNotFound = errors.New("export not found")
+
+	// ErrExportThrottled is returned when a user asks for more exports a day
+	// than maxExportsPerDay.
+	ErrExportThrottled = errors.New("too many exports requested, try again tomorrow")
+
+	// ErrInvalidExportLink is returned for download tokens that are unknown,
+	// expired, or whose export was deleted.
+	ErrInvalidExportLink = errors.New("export link is invalid or has expired")
+)
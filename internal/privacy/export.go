@@ -0,0 +1,166 @@
+package privacy
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/money"
+)
+
+// The archive holds every dataset as JSON and, except for the profile, as CSV.
+// JSON amounts use the API's {"value", "currency"} form.
+type profileJSON struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Email           string     `json:"email"`
+	ProfilePicture  string     `json:"profile_picture,omitempty"`
+	Role            string     `json:"role"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type transactionJSON struct {
+	ID          string       `json:"id"`
+	Amount      money.Amount `json:"amount"`
+	Type        string       `json:"type"`
+	Category    string       `json:"category"`
+	Description string       `json:"description,omitempty"`
+	OccurredAt  time.Time    `json:"occurred_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+type accountJSON struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type journalEntryJSON struct {
+	ID          string        `json:"id"`
+	Description string        `json:"description"`
+	OccurredAt  time.Time     `json:"occurred_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+	Postings    []postingJSON `json:"postings"`
+}
+
+type postingJSON struct {
+	Line      int          `json:"line"`
+	AccountID string       `json:"account_id"`
+	Amount    money.Amount `json:"amount"`
+}
+
+// writeArchive writes data to w as a ZIP archive.
+func writeArchive(w io.Writer, data UserData, now time.Time) error {
+	zw := zip.NewWriter(w)
+
+	profile := profileJSON{
+		ID:             data.Profile.ID,
+		Name:           data.Profile.Name,
+		Email:          data.Profile.Email,
+		ProfilePicture: data.Profile.ProfilePicture,
+		Role:           data.Profile.Role,
+		CreatedAt:      data.Profile.CreatedAt,
+		UpdatedAt:      data.Profile.UpdatedAt,
+	}
+	if !data.Profile.EmailVerifiedAt.IsZero() {
+		profile.EmailVerifiedAt = &data.Profile.EmailVerifiedAt
+	}
+
+	transactions := make([]transactionJSON, len(data.Transactions))
+	transactionRows := [][]string{{"id", "occurred_at", "type", "category", "description", "amount", "currency", "created_at", "updated_at"}}
+	for i, t := range data.Transactions {
+		transactions[i] = transactionJSON(t)
+		transactionRows = append(transactionRows, []string{
+			t.ID, csvTime(t.OccurredAt), t.Type, csvText(t.Category), csvText(t.Description),
+			t.Amount.String(), t.Amount.Currency().Code, csvTime(t.CreatedAt), csvTime(t.UpdatedAt),
+		})
+	}
+
+	accounts := make([]accountJSON, len(data.Accounts))
+	accountRows := [][]string{{"id", "name", "type", "currency", "created_at"}}
+	for i, a := range data.Accounts {
+		accounts[i] = accountJSON(a)
+		accountRows = append(accountRows, []string{a.ID, csvText(a.Name), a.Type, a.Currency, csvTime(a.CreatedAt)})
+	}
+
+	entries := make([]journalEntryJSON, len(data.JournalEntries))
+	postingRows := [][]string{{"journal_entry_id", "occurred_at", "description", "line", "account_id", "amount", "currency"}}
+	for i, e := range data.JournalEntries {
+		entries[i] = journalEntryJSON{
+			ID:          e.ID,
+			Description: e.Description,
+			OccurredAt:  e.OccurredAt,
+			CreatedAt:   e.CreatedAt,
+			Postings:    make([]postingJSON, len(e.Postings)),
+		}
+		for j, p := range e.Postings {
+			entries[i].Postings[j] = postingJSON(p)
+			postingRows = append(postingRows, []string{
+				e.ID, csvTime(e.OccurredAt), csvText(e.Description), strconv.Itoa(p.Line),
+				p.AccountID, p.Amount.String(), p.Amount.Currency().Code,
+			})
+		}
+	}
+
+	files := []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{"profile.json", jsonFile(profile)},
+		{"transactions.json", jsonFile(transactions)},
+		{"transactions.csv", csvFile(transactionRows)},
+		{"ledger/accounts.json", jsonFile(accounts)},
+		{"ledger/accounts.csv", csvFile(accountRows)},
+		{"ledger/journal_entries.json", jsonFile(entries)},
+		{"ledger/postings.csv", csvFile(postingRows)},
+	}
+	for _, f := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return fmt.Errorf("adding %s: %w", f.name, err)
+		}
+		if err := f.write(fw); err != nil {
+			return fmt.Errorf("writing %s: %w", f.name, err)
+		}
+	}
+
+	return zw.Close()
+}
+
+func jsonFile(v any) func(io.Writer) error {
+	return func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+}
+
+func csvFile(rows [][]string) func(io.Writer) error {
+	return func(w io.Writer) error {
+		cw := csv.NewWriter(w)
+		return cw.WriteAll(rows)
+	}
+}
+
+func csvTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// csvText keeps user-entered text from being run as a formula when the CSV is
+// opened in a spreadsheet.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
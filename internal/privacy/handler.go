@@ -0,0 +1,90 @@
+package privacy
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/auth"
+	jsonutil "github.com/Ajay01103/goTransactonsAPI/internal/json"
+)
+
+// Handler holds all HTTP handlers for the privacy domain.
+type Handler struct {
+	service Service
+}
+
+// NewHandler constructs a Handler with the given privacy Service.
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// RequestExport handles POST /users/me/export. The archive is built in the
+// background and its download link emailed; poll GET /users/me/exports for
+// its status.
+func (h *Handler) RequestExport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextKeyUserID).(string)
+	if !ok || userID == "" {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	export, err := h.service.RequestExport(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, ErrExportThrottled) {
+			jsonutil.Write(w, http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+			return
+		}
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to request export"})
+		return
+	}
+
+	jsonutil.Write(w, http.StatusAccepted, export)
+}
+
+// ListExports handles GET /users/me/exports.
+func (h *Handler) ListExports(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextKeyUserID).(string)
+	if !ok || userID == "" {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	exports, err := h.service.ListExports(r.Context(), userID)
+	if err != nil {
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to list exports"})
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, exports)
+}
+
+// DownloadExport handles GET /exports/download?token=, the link emailed once
+// an export is ready. The token is the credential, so no login is needed.
+func (h *Handler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "token is required"})
+		return
+	}
+
+	file, err := h.service.OpenExport(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, ErrInvalidExportLink) {
+			jsonutil.Write(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to load export"})
+		return
+	}
+	defer file.Body.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+file.FileName+`"`)
+	w.Header().Set("Content-Length", strconv.FormatInt(file.Size, 10))
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, file.Body)
+}
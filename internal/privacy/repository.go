@@ -0,0 +1,271 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql"
+	repo "github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql/sqlc"
+	"github.com/Ajay01103/goTransactonsAPI/internal/money"
+)
+
+type postgresRepository struct {
+	queries *repo.Queries
+}
+
+// NewPostgresRepository constructs a privacy Repository backed by sqlc-generated Queries.
+// All sqlc and pgtype details are contained within this file — nothing leaks outward.
+func NewPostgresRepository(queries *repo.Queries) Repository {
+	return &postgresRepository{queries: queries}
+}
+
+func (r *postgresRepository) CreateExport(ctx context.Context, id, userID string) (Export, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).CreateDataExport(ctx, repo.CreateDataExportParams{ID: id, UserID: userID})
+	if err != nil {
+		return Export{}, err
+	}
+	return toExport(row), nil
+}
+
+func (r *postgresRepository) GetOpenExport(ctx context.Context, userID string) (Export, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetOpenDataExport(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Export{}, ErrExportNotFound
+		}
+		return Export{}, err
+	}
+	return toExport(row), nil
+}
+
+func (r *postgresRepository) CountExportsSince(ctx context.Context, userID string, since time.Time) (int, error) {
+	n, err := postgresql.QueriesFromContext(ctx, r.queries).CountDataExportsSince(ctx, repo.CountDataExportsSinceParams{
+		UserID:    userID,
+		CreatedAt: pgtype.Timestamptz{Time: since, Valid: true},
+	})
+	return int(n), err
+}
+
+func (r *postgresRepository) ListExports(ctx context.Context, userID string, limit int) ([]Export, error) {
+	rows, err := postgresql.QueriesFromContext(ctx, r.queries).ListDataExports(ctx, repo.ListDataExportsParams{UserID: userID, Limit: int32(limit)})
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]Export, len(rows))
+	for i, row := range rows {
+		exports[i] = toExport(row)
+	}
+	return exports, nil
+}
+
+func (r *postgresRepository) ClaimExport(ctx context.Context, staleBefore time.Time) (Export, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).ClaimDataExport(ctx, pgtype.Timestamptz{Time: staleBefore, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Export{}, ErrExportNotFound
+		}
+		return Export{}, err
+	}
+	return toExport(row), nil
+}
+
+func (r *postgresRepository) CompleteExport(ctx context.Context, params CompleteExportParams) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).CompleteDataExport(ctx, repo.CompleteDataExportParams{
+		ID:        params.ID,
+		BlobKey:   pgtype.Text{String: params.BlobKey, Valid: true},
+		TokenHash: pgtype.Text{String: params.TokenHash, Valid: true},
+		SizeBytes: pgtype.Int8{Int64: params.SizeBytes, Valid: true},
+		ExpiresAt: pgtype.Timestamptz{Time: params.ExpiresAt, Valid: true},
+	})
+}
+
+func (r *postgresRepository) FailExport(ctx context.Context, id string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).FailDataExport(ctx, id)
+}
+
+func (r *postgresRepository) GetExportByTokenHash(ctx context.Context, tokenHash string) (Export, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetDataExportByTokenHash(ctx, pgtype.Text{String: tokenHash, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Export{}, ErrInvalidExportLink
+		}
+		return Export{}, err
+	}
+	return toExport(row), nil
+}
+
+func (r *postgresRepository) ListExpiredExports(ctx context.Context, limit int) ([]Export, error) {
+	rows, err := postgresql.QueriesFromContext(ctx, r.queries).ListExpiredDataExports(ctx, int32(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]Export, len(rows))
+	for i, row := range rows {
+		exports[i] = toExport(row)
+	}
+	return exports, nil
+}
+
+func (r *postgresRepository) ExpireExport(ctx context.Context, id string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).ExpireDataExport(ctx, id)
+}
+
+func (r *postgresRepository) GetUserData(ctx context.Context, userID string) (UserData, error) {
+	q := postgresql.QueriesFromContext(ctx, r.queries)
+
+	user, err := q.GetUserByID(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	data := UserData{
+		Profile: Profile{
+			ID:              user.ID,
+			Name:            user.Name,
+			Email:           user.Email,
+			ProfilePicture:  user.ProfilePicture.String,
+			Role:            user.Role,
+			EmailVerifiedAt: user.EmailVerifiedAt.Time,
+			CreatedAt:       user.CreatedAt.Time,
+			UpdatedAt:       user.UpdatedAt.Time,
+		},
+	}
+
+	transactions, err := q.ListTransactionsForExport(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	for _, row := range transactions {
+		amount, err := toAmount(row.Amount, row.Currency)
+		if err != nil {
+			return UserData{}, err
+		}
+		data.Transactions = append(data.Transactions, Transaction{
+			ID:          row.ID,
+			Amount:      amount,
+			Type:        row.Type,
+			Category:    row.Category,
+			Description: row.Description.String,
+			OccurredAt:  row.OccurredAt.Time,
+			CreatedAt:   row.CreatedAt.Time,
+			UpdatedAt:   row.UpdatedAt.Time,
+		})
+	}
+
+	accounts, err := q.ListAccountsForExport(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	for _, row := range accounts {
+		data.Accounts = append(data.Accounts, Account{
+			ID:        row.ID,
+			Name:      row.Name,
+			Type:      row.Type,
+			Currency:  row.Currency,
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+
+	entries, err := q.ListJournalEntriesForExport(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	postings, err := q.ListPostingsForExport(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	// postings arrive in the same entry order, grouped by entry
+	byEntry := make(map[string][]Posting, len(entries))
+	for _, row := range postings {
+		amount, err := toAmount(row.Amount, row.Currency)
+		if err != nil {
+			return UserData{}, err
+		}
+		byEntry[row.JournalEntryID] = append(byEntry[row.JournalEntryID], Posting{
+			Line:      int(row.Line),
+			AccountID: row.AccountID,
+			Amount:    amount,
+		})
+	}
+	for _, row := range entries {
+		data.JournalEntries = append(data.JournalEntries, JournalEntry{
+			ID:          row.ID,
+			Description: row.Description,
+			OccurredAt:  row.OccurredAt.Time,
+			CreatedAt:   row.CreatedAt.Time,
+			Postings:    byEntry[row.ID],
+		})
+	}
+
+	return data, nil
+}
+
+func (r *postgresRepository) ListUsersDueForPurge(ctx context.Context, deletedBefore time.Time, limit int) ([]DeletedUser, error) {
+	rows, err := postgresql.QueriesFromContext(ctx, r.queries).ListUsersDueForPurge(ctx, repo.ListUsersDueForPurgeParams{
+		DeletedAt: pgtype.Timestamptz{Time: deletedBefore, Valid: true},
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]DeletedUser, len(rows))
+	for i, row := range rows {
+		users[i] = DeletedUser{ID: row.ID, Email: row.Email}
+	}
+	return users, nil
+}
+
+func (r *postgresRepository) ClaimPurge(ctx context.Context, userID string, deletedBefore time.Time) (bool, error) {
+	n, err := postgresql.QueriesFromContext(ctx, r.queries).MarkUserPurging(ctx, repo.MarkUserPurgingParams{
+		ID:        userID,
+		DeletedAt: pgtype.Timestamptz{Time: deletedBefore, Valid: true},
+	})
+	return n > 0, err
+}
+
+func (r *postgresRepository) PurgeUser(ctx context.Context, userID string) (bool, error) {
+	n, err := postgresql.QueriesFromContext(ctx, r.queries).PurgeUser(ctx, userID)
+	return n > 0, err
+}
+
+func (r *postgresRepository) ClearLoginThrottle(ctx context.Context, email string) error {
+	// same key as the auth package's LoginThrottle
+	return postgresql.QueriesFromContext(ctx, r.queries).ClearLoginFailures(ctx, "email:"+strings.ToLower(strings.TrimSpace(email)))
+}
+
+func (r *postgresRepository) CreateAuditEntry(ctx context.Context, id, actorID, action, targetUserID string) error {
+	return postgresql.QueriesFromContext(ctx, r.queries).CreateAdminAuditEntry(ctx, repo.CreateAdminAuditEntryParams{
+		ID:           id,
+		ActorID:      actorID,
+		Action:       action,
+		TargetUserID: targetUserID,
+	})
+}
+
+func toExport(row repo.DataExport) Export {
+	return Export{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		Status:      row.Status,
+		BlobKey:     row.BlobKey.String,
+		SizeBytes:   row.SizeBytes.Int64,
+		CreatedAt:   row.CreatedAt.Time,
+		CompletedAt: row.CompletedAt.Time,
+		ExpiresAt:   row.ExpiresAt.Time,
+	}
+}
+
+func toAmount(d money.Decimal, code string) (money.Amount, error) {
+	currency, err := money.LookupCurrency(code)
+	if err != nil {
+		return money.Amount{}, err
+	}
+	return money.FromDecimal(d, currency)
+}
@@ -0,0 +1,346 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/lucsky/cuid"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/blobstore"
+	"github.com/Ajay01103/goTransactonsAPI/internal/mailer"
+)
+
+const (
+	// maxExportsPerDay caps how many exports a user can request in 24 hours.
+	maxExportsPerDay = 3
+	// exportListLimit is how many of a user's exports ListExports returns.
+	exportListLimit = 10
+	// exportStaleAfter is how long an export may stay processing before
+	// another worker takes it over, e.g. after a crash.
+	exportStaleAfter = 30 * time.Minute
+	// batchSize bounds how many expired exports or deleted accounts one pass handles.
+	batchSize = 50
+	// mailTimeout bounds how long sending one email may take.
+	mailTimeout = 10 * time.Second
+)
+
+// userBlobPrefixes are the Store key prefixes under which each user's files
+// live, followed by the user ID: avatars (see the users package) and exports.
+var userBlobPrefixes = []string{"avatars", "exports"}
+
+type svc struct {
+	repo   Repository
+	tx     TxRunner
+	mailer mailer.Mailer
+	cfg    Config
+
+	wake chan struct{} // nudges Run when an export is requested
+}
+
+// NewService wires a privacy Repository, a TxRunner and the Mailer that
+// delivers download links into a Service. Call Run to process exports and
+// purges in the background.
+func NewService(repo Repository, tx TxRunner, m mailer.Mailer, cfg Config) Service {
+	return &svc{repo: repo, tx: tx, mailer: m, cfg: cfg, wake: make(chan struct{}, 1)}
+}
+
+// RequestExport queues an export of the user's data. While one is still being
+// built it is returned instead of queueing another.
+func (s *svc) RequestExport(ctx context.Context, userID string) (ExportResponse, error) {
+	var export Export
+	err := s.tx.Atomic(ctx, func(ctx context.Context) error {
+		open, err := s.repo.GetOpenExport(ctx, userID)
+		if err == nil {
+			export = open
+			return nil
+		}
+		if !errors.Is(err, ErrExportNotFound) {
+			return fmt.Errorf("checking open exports: %w", err)
+		}
+
+		requested, err := s.repo.CountExportsSince(ctx, userID, time.Now().Add(-24*time.Hour))
+		if err != nil {
+			return fmt.Errorf("counting exports: %w", err)
+		}
+		if requested >= maxExportsPerDay {
+			return ErrExportThrottled
+		}
+
+		export, err = s.repo.CreateExport(ctx, cuid.New(), userID)
+		if err != nil {
+			return fmt.Errorf("creating export: %w", err)
+		}
+		return s.repo.CreateAuditEntry(ctx, cuid.New(), userID, ActionRequestExport, userID)
+	})
+	if err != nil {
+		if errors.Is(err, ErrExportThrottled) {
+			return ExportResponse{}, err
+		}
+		return ExportResponse{}, fmt.Errorf("requesting export: %w", err)
+	}
+
+	s.notify()
+	return toExportResponse(export), nil
+}
+
+// ListExports returns the user's most recent exports, newest first.
+func (s *svc) ListExports(ctx context.Context, userID string) (ExportListResponse, error) {
+	exports, err := s.repo.ListExports(ctx, userID, exportListLimit)
+	if err != nil {
+		return ExportListResponse{}, fmt.Errorf("listing exports: %w", err)
+	}
+
+	resp := ExportListResponse{Exports: make([]ExportResponse, 0, len(exports))}
+	for _, e := range exports {
+		resp.Exports = append(resp.Exports, toExportResponse(e))
+	}
+	return resp, nil
+}
+
+// OpenExport returns the archive behind a download token. Every download is
+// recorded in the audit log; the link keeps working until the export expires.
+func (s *svc) OpenExport(ctx context.Context, token string) (ExportFile, error) {
+	export, err := s.repo.GetExportByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, ErrInvalidExportLink) {
+			return ExportFile{}, err
+		}
+		return ExportFile{}, fmt.Errorf("loading export: %w", err)
+	}
+
+	obj, err := s.cfg.Store.Get(ctx, export.BlobKey)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) {
+			return ExportFile{}, ErrInvalidExportLink
+		}
+		return ExportFile{}, fmt.Errorf("opening export: %w", err)
+	}
+
+	if err := s.repo.CreateAuditEntry(ctx, cuid.New(), export.UserID, ActionDownloadExport, export.UserID); err != nil {
+		obj.Body.Close()
+		return ExportFile{}, fmt.Errorf("recording audit entry: %w", err)
+	}
+
+	return ExportFile{
+		Object:   obj,
+		FileName: "export-" + export.CreatedAt.UTC().Format("2006-01-02") + ".zip",
+	}, nil
+}
+
+// Run processes queued exports, expired exports and deleted accounts every
+// interval, and right away when an export is requested, until ctx is done.
+func (s *svc) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for s.buildNextExport(ctx) {
+		}
+		s.expireExports(ctx)
+		s.purgeDeletedAccounts(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// notify wakes Run without blocking; one pending wake-up is enough.
+func (s *svc) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// buildNextExport claims and builds one queued export, marking it failed if
+// that goes wrong. It reports whether there was one.
+func (s *svc) buildNextExport(ctx context.Context) bool {
+	export, err := s.repo.ClaimExport(ctx, time.Now().Add(-exportStaleAfter))
+	if err != nil {
+		if !errors.Is(err, ErrExportNotFound) {
+			slog.ErrorContext(ctx, "claiming data export", "error", err)
+		}
+		return false
+	}
+
+	if err := s.buildExport(ctx, export); err != nil {
+		slog.ErrorContext(ctx, "building data export", "export_id", export.ID, "error", err)
+		if err := s.repo.FailExport(ctx, export.ID); err != nil {
+			slog.ErrorContext(ctx, "marking data export failed", "export_id", export.ID, "error", err)
+		}
+	}
+	return true
+}
+
+// buildExport writes the user's data to a ZIP archive in the Store, records
+// its download token and emails the link.
+func (s *svc) buildExport(ctx context.Context, export Export) error {
+	// one snapshot, so a transfer made mid-export cannot show up in the
+	// transactions but not the ledger
+	var data UserData
+	err := s.tx.Snapshot(ctx, func(ctx context.Context) error {
+		var err error
+		data, err = s.repo.GetUserData(ctx, export.UserID)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("loading user data: %w", err)
+	}
+
+	// spool to disk, since the Store needs the size up front
+	f, err := os.CreateTemp("", "export-*.zip")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	now := time.Now()
+	if err := writeArchive(f, data, now); err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("sizing archive: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewinding archive: %w", err)
+	}
+
+	key := exportKey(export.UserID, export.ID)
+	if err := s.cfg.Store.Put(ctx, key, f, size, "application/zip"); err != nil {
+		return fmt.Errorf("storing archive: %w", err)
+	}
+
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := now.Add(s.cfg.ExportTTL)
+	err = s.repo.CompleteExport(ctx, CompleteExportParams{
+		ID:        export.ID,
+		BlobKey:   key,
+		TokenHash: hash,
+		SizeBytes: size,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("completing export: %w", err)
+	}
+
+	s.send(ctx, exportReadyEmail(data.Profile, withToken(s.cfg.ExportDownloadURL, raw), expiresAt))
+	return nil
+}
+
+// expireExports deletes the archives of exports past their expiry.
+func (s *svc) expireExports(ctx context.Context) {
+	exports, err := s.repo.ListExpiredExports(ctx, batchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "listing expired data exports", "error", err)
+		return
+	}
+
+	for _, e := range exports {
+		if err := s.cfg.Store.Delete(ctx, e.BlobKey); err != nil {
+			slog.ErrorContext(ctx, "deleting data export", "export_id", e.ID, "error", err)
+			continue
+		}
+		if err := s.repo.ExpireExport(ctx, e.ID); err != nil {
+			slog.ErrorContext(ctx, "expiring data export", "export_id", e.ID, "error", err)
+		}
+	}
+}
+
+// purgeDeletedAccounts permanently erases accounts deleted more than
+// DeletionGrace ago.
+func (s *svc) purgeDeletedAccounts(ctx context.Context) {
+	cutoff := time.Now().Add(-s.cfg.DeletionGrace)
+	users, err := s.repo.ListUsersDueForPurge(ctx, cutoff, batchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "listing deleted accounts", "error", err)
+		return
+	}
+
+	for _, u := range users {
+		if err := s.purge(ctx, u, cutoff); err != nil {
+			slog.ErrorContext(ctx, "purging deleted account", "user_id", u.ID, "error", err)
+		}
+	}
+}
+
+// purge erases one account: its files, every row it owns and the login
+// throttle kept under its email. Only the opaque user ID survives, in the
+// audit log. The account is claimed first, so it cannot be restored once its
+// files start going, and files go before rows, so a failure leaves the
+// claimed account to be retried rather than files nothing refers to.
+func (s *svc) purge(ctx context.Context, u DeletedUser, cutoff time.Time) error {
+	claimed, err := s.repo.ClaimPurge(ctx, u.ID, cutoff)
+	if err != nil {
+		return fmt.Errorf("claiming user: %w", err)
+	}
+	if !claimed {
+		// restored meanwhile
+		return nil
+	}
+
+	for _, prefix := range userBlobPrefixes {
+		if err := s.cfg.Store.DeletePrefix(ctx, prefix+"/"+u.ID); err != nil {
+			return fmt.Errorf("deleting %s: %w", prefix, err)
+		}
+	}
+
+	return s.tx.Atomic(ctx, func(ctx context.Context) error {
+		purged, err := s.repo.PurgeUser(ctx, u.ID)
+		if err != nil {
+			return fmt.Errorf("deleting user: %w", err)
+		}
+		if !purged {
+			// purged by another replica
+			return nil
+		}
+		if err := s.repo.ClearLoginThrottle(ctx, u.Email); err != nil {
+			return fmt.Errorf("clearing login throttle: %w", err)
+		}
+		return s.repo.CreateAuditEntry(ctx, cuid.New(), SystemActor, ActionPurgeAccount, u.ID)
+	})
+}
+
+// send delivers msg with a bounded timeout, logging failures.
+func (s *svc) send(ctx context.Context, msg mailer.Message) {
+	ctx, cancel := context.WithTimeout(ctx, mailTimeout)
+	defer cancel()
+
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		slog.ErrorContext(ctx, "sending email", "subject", msg.Subject, "error", err)
+	}
+}
+
+// exportKey is where an export's archive lives in the Store.
+func exportKey(userID, exportID string) string {
+	return "exports/" + userID + "/" + exportID + ".zip"
+}
+
+func toExportResponse(e Export) ExportResponse {
+	resp := ExportResponse{
+		ID:        e.ID,
+		Status:    e.Status,
+		CreatedAt: e.CreatedAt,
+	}
+	if !e.CompletedAt.IsZero() {
+		resp.CompletedAt = &e.CompletedAt
+	}
+	if e.Status == ExportReady {
+		resp.SizeBytes = e.SizeBytes
+		resp.ExpiresAt = &e.ExpiresAt
+	}
+	return resp
+}
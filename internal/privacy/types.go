@@ -0,0 +1,216 @@
+package privacy
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/blobstore"
+	"github.com/Ajay01103/goTransactonsAPI/internal/money"
+)
+
+// ── Configuration ─────────────────────────────────────────────────────────────
+
+// Config holds the settings for data exports and account purging.
+type Config struct {
+	Store             blobstore.BlobStore // holds finished export archives
+	ExportTTL         time.Duration       // how long a finished export can be downloaded
+	ExportDownloadURL string              // download link target; ?token= is appended
+	DeletionGrace     time.Duration       // how long a deleted account waits before it is purged
+}
+
+// ── Domain model ──────────────────────────────────────────────────────────────
+
+// Action names recorded in the audit log. Deleting an account is recorded by
+// the auth package as "account.delete".
+const (
+	ActionRequestExport  = "account.export"
+	ActionDownloadExport = "account.export_download"
+	ActionPurgeAccount   = "account.purge"
+)
+
+// SystemActor is the audit log actor of actions the API takes on its own.
+const SystemActor = "system"
+
+// Export statuses. An export moves from pending through processing to ready
+// or failed; a ready export becomes expired once its archive is deleted.
+const (
+	ExportPending    = "pending"
+	ExportProcessing = "processing"
+	ExportReady      = "ready"
+	ExportFailed     = "failed"
+	ExportExpired    = "expired"
+)
+
+// Export is a user's request for a copy of their data.
+type Export struct {
+	ID          string
+	UserID      string
+	Status      string
+	BlobKey     string // set while ready
+	SizeBytes   int64
+	CreatedAt   time.Time
+	CompletedAt time.Time // zero until ready or failed
+	ExpiresAt   time.Time // zero until ready
+}
+
+// Profile is the account data included in an export — no password hash.
+type Profile struct {
+	ID              string
+	Name            string
+	Email           string
+	ProfilePicture  string
+	Role            string
+	EmailVerifiedAt time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Transaction is one income or expense record of the user.
+type Transaction struct {
+	ID          string
+	Amount      money.Amount
+	Type        string
+	Category    string
+	Description string
+	OccurredAt  time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Account is one of the user's ledger accounts.
+type Account struct {
+	ID        string
+	Name      string
+	Type      string
+	Currency  string
+	CreatedAt time.Time
+}
+
+// JournalEntry is a ledger entry with its postings in line order.
+type JournalEntry struct {
+	ID          string
+	Description string
+	OccurredAt  time.Time
+	CreatedAt   time.Time
+	Postings    []Posting
+}
+
+// Posting is one line of a journal entry; positive amounts are debits.
+type Posting struct {
+	Line      int
+	AccountID string
+	Amount    money.Amount
+}
+
+// UserData is everything an export contains.
+type UserData struct {
+	Profile        Profile
+	Transactions   []Transaction
+	Accounts       []Account
+	JournalEntries []JournalEntry
+}
+
+// DeletedUser is an account whose grace period has passed.
+type DeletedUser struct {
+	ID    string
+	Email string
+}
+
+// ── Service DTOs ──────────────────────────────────────────────────────────────
+
+// ExportResponse is the public DTO for an export. The download link is only
+// ever sent by email.
+type ExportResponse struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	SizeBytes   int64      `json:"size_bytes,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// ExportListResponse lists a user's most recent exports, newest first.
+type ExportListResponse struct {
+	Exports []ExportResponse `json:"exports"`
+}
+
+// ExportFile is a finished export archive opened for download.
+type ExportFile struct {
+	blobstore.Object
+	FileName string
+}
+
+// ── Repository DTOs ───────────────────────────────────────────────────────────
+
+// CompleteExportParams records a finished export.
+type CompleteExportParams struct {
+	ID        string
+	BlobKey   string
+	TokenHash string
+	SizeBytes int64
+	ExpiresAt time.Time
+}
+
+// ── Contracts ─────────────────────────────────────────────────────────────────
+
+// Repository defines the data-access contract for the privacy domain.
+// All method signatures use domain types only — no sqlc or pgtype.
+type Repository interface {
+	CreateExport(ctx context.Context, id, userID string) (Export, error)
+	// GetOpenExport returns ErrExportNotFound unless an export of the user is
+	// pending or processing.
+	GetOpenExport(ctx context.Context, userID string) (Export, error)
+	CountExportsSince(ctx context.Context, userID string, since time.Time) (int, error)
+	ListExports(ctx context.Context, userID string, limit int) ([]Export, error)
+	// ClaimExport marks the oldest pending export, or one left processing
+	// since before staleBefore, as processing. Returns ErrExportNotFound if
+	// there is none.
+	ClaimExport(ctx context.Context, staleBefore time.Time) (Export, error)
+	CompleteExport(ctx context.Context, params CompleteExportParams) error
+	FailExport(ctx context.Context, id string) error
+	// GetExportByTokenHash returns ErrInvalidExportLink unless the export is
+	// ready and not yet expired.
+	GetExportByTokenHash(ctx context.Context, tokenHash string) (Export, error)
+	ListExpiredExports(ctx context.Context, limit int) ([]Export, error)
+	ExpireExport(ctx context.Context, id string) error
+
+	// GetUserData loads everything an export contains. It reads in several
+	// statements, so run it inside TxRunner.Snapshot.
+	GetUserData(ctx context.Context, userID string) (UserData, error)
+
+	ListUsersDueForPurge(ctx context.Context, deletedBefore time.Time, limit int) ([]DeletedUser, error)
+	// ClaimPurge marks the account as being purged, after which it can no
+	// longer be restored, unless it was restored or deleted after
+	// deletedBefore meanwhile. It reports whether it did.
+	ClaimPurge(ctx context.Context, userID string, deletedBefore time.Time) (bool, error)
+	// PurgeUser deletes a claimed user and every row it owns. It reports
+	// whether it did.
+	PurgeUser(ctx context.Context, userID string) (bool, error)
+	// ClearLoginThrottle forgets failed logins recorded under the email.
+	ClearLoginThrottle(ctx context.Context, email string) error
+
+	CreateAuditEntry(ctx context.Context, id, actorID, action, targetUserID string) error
+}
+
+// TxRunner runs fn as a single unit of work, so an action and its audit
+// entry are committed together. Snapshot runs read-only fn against one
+// consistent view of the database.
+type TxRunner interface {
+	Atomic(ctx context.Context, fn func(ctx context.Context) error) error
+	Snapshot(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Service defines the business-logic contract for the privacy domain.
+type Service interface {
+	// RequestExport queues an export of the user's data; it is built in the
+	// background and the download link is emailed.
+	RequestExport(ctx context.Context, userID string) (ExportResponse, error)
+	ListExports(ctx context.Context, userID string) (ExportListResponse, error)
+	// OpenExport returns the archive behind an emailed download link.
+	OpenExport(ctx context.Context, token string) (ExportFile, error)
+
+	// Run builds queued exports, deletes expired ones and purges deleted
+	// accounts, every interval and whenever an export is requested, until
+	// ctx is done.
+	Run(ctx context.Context, interval time.Duration)
+}
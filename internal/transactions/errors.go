@@ -0,0 +1,18 @@
+package transactions
+
+import "errors"
+
+// Sentinel errors for the transactions domain.
+var (
+	// ErrNotFound is returned when a transaction does not exist or belongs to another user.
+	ErrNotFound = errors.New("transaction not found")
+
+	// ErrInvalidAmount is returned when an amount is not positive or does not fit the amount column.
+	ErrInvalidAmount = errors.New("amount must be positive and less than 10^15")
+
+	// ErrInvalidType is returned when a transaction type is neither "income" nor "expense".
+	ErrInvalidType = errors.New(`type must be "income" or "expense"`)
+
+	// ErrInvalidCategory is returned when the category is empty.
+	ErrInvalidCategory = errors.New("category is required")
+)
@@ -0,0 +1,173 @@
+package transactions
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/auth"
+	jsonutil "github.com/Ajay01103/goTransactonsAPI/internal/json"
+	"github.com/Ajay01103/goTransactonsAPI/internal/money"
+)
+
+// Handler holds all HTTP handlers for the transactions domain.
+type Handler struct {
+	service Service
+}
+
+// NewHandler constructs a Handler with the given transactions Service.
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+type createRequest struct {
+	Amount      *money.Amount `json:"amount"`
+	Type        Type          `json:"type"`
+	Category    string        `json:"category"`
+	Description string        `json:"description,omitempty"`
+	OccurredAt  time.Time     `json:"occurred_at"`
+}
+
+type updateRequest struct {
+	Amount      *money.Amount `json:"amount,omitempty"`
+	Type        *Type         `json:"type,omitempty"`
+	Category    *string       `json:"category,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	OccurredAt  *time.Time    `json:"occurred_at,omitempty"`
+}
+
+// List handles GET /transactions.
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	resp, err := h.service.List(r.Context(), userID, ListInput{Limit: limit, Offset: offset})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, resp)
+}
+
+// Get handles GET /transactions/{id}.
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.Get(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, resp)
+}
+
+// Create handles POST /transactions.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req createRequest
+	if err := jsonutil.Read(r, &req); err != nil {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if req.Amount == nil || req.Type == "" || req.Category == "" {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": "amount, type and category are required"})
+		return
+	}
+
+	resp, err := h.service.Create(r.Context(), userID, CreateInput{
+		Amount:      *req.Amount,
+		Type:        req.Type,
+		Category:    req.Category,
+		Description: req.Description,
+		OccurredAt:  req.OccurredAt,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	jsonutil.Write(w, http.StatusCreated, resp)
+}
+
+// Update handles PATCH /transactions/{id}.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req updateRequest
+	if err := jsonutil.Read(r, &req); err != nil {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.Update(r.Context(), userID, chi.URLParam(r, "id"), UpdateInput{
+		Amount:      req.Amount,
+		Type:        req.Type,
+		Category:    req.Category,
+		Description: req.Description,
+		OccurredAt:  req.OccurredAt,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, resp)
+}
+
+// Delete handles DELETE /transactions/{id}.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// currentUserID reads the userID set by auth.RequireAuth, writing a 401 if absent.
+func currentUserID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID, ok := r.Context().Value(auth.ContextKeyUserID).(string)
+	if !ok || userID == "" {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return "", false
+	}
+	return userID, true
+}
+
+// writeError maps domain errors onto HTTP status codes.
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		jsonutil.Write(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrInvalidAmount), errors.Is(err, ErrInvalidType), errors.Is(err, ErrInvalidCategory):
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,156 @@
+package transactions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql"
+	repo "github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql/sqlc"
+	"github.com/Ajay01103/goTransactonsAPI/internal/money"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type postgresRepository struct {
+	queries *repo.Queries
+}
+
+// NewPostgresRepository constructs a transactions Repository backed by sqlc-generated Queries.
+// All sqlc and pgtype details are contained within this file — nothing leaks outward.
+func NewPostgresRepository(queries *repo.Queries) Repository {
+	return &postgresRepository{queries: queries}
+}
+
+func (r *postgresRepository) Create(ctx context.Context, params CreateParams) (Transaction, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).CreateTransaction(ctx, repo.CreateTransactionParams{
+		ID:          params.ID,
+		UserID:      params.UserID,
+		Amount:      params.Amount.Decimal(),
+		Currency:    params.Amount.Currency().Code,
+		Type:        string(params.Type),
+		Category:    params.Category,
+		Description: toText(params.Description),
+		OccurredAt:  pgtype.Timestamptz{Time: params.OccurredAt, Valid: true},
+	})
+	if err != nil {
+		return Transaction{}, mapError(err)
+	}
+
+	return toDomain(row)
+}
+
+func (r *postgresRepository) Get(ctx context.Context, userID, id string) (Transaction, error) {
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetTransaction(ctx, repo.GetTransactionParams{ID: id, UserID: userID})
+	if err != nil {
+		return Transaction{}, mapError(err)
+	}
+
+	return toDomain(row)
+}
+
+func (r *postgresRepository) List(ctx context.Context, userID string, limit, offset int) ([]Transaction, error) {
+	rows, err := postgresql.QueriesFromContext(ctx, r.queries).ListTransactions(ctx, repo.ListTransactionsParams{
+		UserID: userID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Transaction, 0, len(rows))
+	for _, row := range rows {
+		t, err := toDomain(row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+
+	return out, nil
+}
+
+func (r *postgresRepository) Update(ctx context.Context, params UpdateParams) (Transaction, error) {
+	args := repo.UpdateTransactionParams{
+		ID:     params.ID,
+		UserID: params.UserID,
+	}
+	if params.Amount != nil {
+		args.Amount = params.Amount.Decimal()
+		args.Currency = pgtype.Text{String: params.Amount.Currency().Code, Valid: true}
+	}
+	if params.Type != nil {
+		args.Type = pgtype.Text{String: string(*params.Type), Valid: true}
+	}
+	if params.Category != nil {
+		args.Category = pgtype.Text{String: *params.Category, Valid: true}
+	}
+	if params.Description != nil {
+		args.Description = pgtype.Text{String: *params.Description, Valid: true}
+	}
+	if params.OccurredAt != nil {
+		args.OccurredAt = pgtype.Timestamptz{Time: *params.OccurredAt, Valid: true}
+	}
+
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).UpdateTransaction(ctx, args)
+	if err != nil {
+		return Transaction{}, mapError(err)
+	}
+
+	return toDomain(row)
+}
+
+func (r *postgresRepository) Delete(ctx context.Context, userID, id string) error {
+	n, err := postgresql.QueriesFromContext(ctx, r.queries).DeleteTransaction(ctx, repo.DeleteTransactionParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func toDomain(row repo.Transaction) (Transaction, error) {
+	currency, err := money.LookupCurrency(row.Currency)
+	if err != nil {
+		return Transaction{}, err
+	}
+	amount, err := money.FromDecimal(row.Amount, currency)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	return Transaction{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		Amount:      amount,
+		Type:        Type(row.Type),
+		Category:    row.Category,
+		Description: row.Description.String,
+		OccurredAt:  row.OccurredAt.Time,
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}, nil
+}
+
+func toText(s string) pgtype.Text {
+	if s == "" {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: s, Valid: true}
+}
+
+// mapError translates storage errors into domain sentinels.
+func mapError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "22003" { // numeric_value_out_of_range
+		return ErrInvalidAmount
+	}
+	return err
+}
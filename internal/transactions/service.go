@@ -0,0 +1,159 @@
+package transactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lucsky/cuid"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 100
+)
+
+type svc struct {
+	repo Repository
+}
+
+// NewService wires a transactions Repository into a Service.
+func NewService(repo Repository) Service {
+	return &svc{repo: repo}
+}
+
+// Create validates the input and records a new transaction owned by userID.
+func (s *svc) Create(ctx context.Context, userID string, input CreateInput) (TransactionResponse, error) {
+	if !input.Amount.IsPositive() {
+		return TransactionResponse{}, ErrInvalidAmount
+	}
+	if !input.Type.Valid() {
+		return TransactionResponse{}, ErrInvalidType
+	}
+	category := strings.TrimSpace(input.Category)
+	if category == "" {
+		return TransactionResponse{}, ErrInvalidCategory
+	}
+
+	occurredAt := input.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+
+	t, err := s.repo.Create(ctx, CreateParams{
+		ID:          cuid.New(),
+		UserID:      userID,
+		Amount:      input.Amount,
+		Type:        input.Type,
+		Category:    category,
+		Description: strings.TrimSpace(input.Description),
+		OccurredAt:  occurredAt,
+	})
+	if err != nil {
+		return TransactionResponse{}, wrap("creating transaction", err)
+	}
+
+	return toResponse(t), nil
+}
+
+// Get returns a single transaction, or ErrNotFound if it is not owned by userID.
+func (s *svc) Get(ctx context.Context, userID, id string) (TransactionResponse, error) {
+	t, err := s.repo.Get(ctx, userID, id)
+	if err != nil {
+		return TransactionResponse{}, wrap("fetching transaction", err)
+	}
+
+	return toResponse(t), nil
+}
+
+// List returns a page of the user's transactions, newest first.
+func (s *svc) List(ctx context.Context, userID string, input ListInput) (ListResponse, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	offset := max(input.Offset, 0)
+
+	items, err := s.repo.List(ctx, userID, limit, offset)
+	if err != nil {
+		return ListResponse{}, fmt.Errorf("listing transactions: %w", err)
+	}
+
+	resp := ListResponse{
+		Transactions: make([]TransactionResponse, 0, len(items)),
+		Limit:        limit,
+		Offset:       offset,
+	}
+	for _, t := range items {
+		resp.Transactions = append(resp.Transactions, toResponse(t))
+	}
+
+	return resp, nil
+}
+
+// Update applies a partial update to a transaction owned by userID.
+func (s *svc) Update(ctx context.Context, userID, id string, input UpdateInput) (TransactionResponse, error) {
+	if input.Amount != nil && !input.Amount.IsPositive() {
+		return TransactionResponse{}, ErrInvalidAmount
+	}
+	if input.Type != nil && !input.Type.Valid() {
+		return TransactionResponse{}, ErrInvalidType
+	}
+	if input.Category != nil {
+		category := strings.TrimSpace(*input.Category)
+		if category == "" {
+			return TransactionResponse{}, ErrInvalidCategory
+		}
+		input.Category = &category
+	}
+
+	t, err := s.repo.Update(ctx, UpdateParams{
+		ID:          id,
+		UserID:      userID,
+		Amount:      input.Amount,
+		Type:        input.Type,
+		Category:    input.Category,
+		Description: input.Description,
+		OccurredAt:  input.OccurredAt,
+	})
+	if err != nil {
+		return TransactionResponse{}, wrap("updating transaction", err)
+	}
+
+	return toResponse(t), nil
+}
+
+// Delete removes a transaction owned by userID.
+func (s *svc) Delete(ctx context.Context, userID, id string) error {
+	if err := s.repo.Delete(ctx, userID, id); err != nil {
+		return wrap("deleting transaction", err)
+	}
+	return nil
+}
+
+// wrap propagates sentinel errors unwrapped so callers can detect them, and
+// adds context to everything else.
+func wrap(op string, err error) error {
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrInvalidAmount) {
+		return err
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+func toResponse(t Transaction) TransactionResponse {
+	return TransactionResponse{
+		ID:          t.ID,
+		Amount:      t.Amount,
+		Type:        t.Type,
+		Category:    t.Category,
+		Description: t.Description,
+		OccurredAt:  t.OccurredAt,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
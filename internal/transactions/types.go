@@ -0,0 +1,130 @@
+package transactions
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/money"
+)
+
+// ── Domain model ──────────────────────────────────────────────────────────────
+
+// Type distinguishes money coming in from money going out.
+type Type string
+
+const (
+	TypeIncome  Type = "income"
+	TypeExpense Type = "expense"
+)
+
+// Valid reports whether t is one of the known transaction types.
+func (t Type) Valid() bool {
+	return t == TypeIncome || t == TypeExpense
+}
+
+// Transaction is the internal domain model — no storage-layer types.
+type Transaction struct {
+	ID          string
+	UserID      string
+	Amount      money.Amount
+	Type        Type
+	Category    string
+	Description string
+	OccurredAt  time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// ── Service DTOs ──────────────────────────────────────────────────────────────
+
+// CreateInput is the DTO passed from handler → service when recording a transaction.
+type CreateInput struct {
+	Amount      money.Amount
+	Type        Type
+	Category    string
+	Description string
+	OccurredAt  time.Time // zero value means "now"
+}
+
+// UpdateInput is the DTO passed from handler → service for a partial update.
+// Nil fields are left untouched. Amount carries its currency, so changing
+// either always sets both together.
+type UpdateInput struct {
+	Amount      *money.Amount
+	Type        *Type
+	Category    *string
+	Description *string
+	OccurredAt  *time.Time
+}
+
+// ListInput carries pagination for listing a user's transactions.
+type ListInput struct {
+	Limit  int
+	Offset int
+}
+
+// TransactionResponse is the public DTO returned from service → handler.
+type TransactionResponse struct {
+	ID          string       `json:"id"`
+	Amount      money.Amount `json:"amount"`
+	Type        Type         `json:"type"`
+	Category    string       `json:"category"`
+	Description string       `json:"description,omitempty"`
+	OccurredAt  time.Time    `json:"occurred_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// ListResponse wraps a page of transactions.
+type ListResponse struct {
+	Transactions []TransactionResponse `json:"transactions"`
+	Limit        int                   `json:"limit"`
+	Offset       int                   `json:"offset"`
+}
+
+// ── Repository DTOs ───────────────────────────────────────────────────────────
+
+// CreateParams carries the data needed to persist a new transaction.
+// Uses only plain Go types — no sqlc or pgtype here.
+type CreateParams struct {
+	ID          string
+	UserID      string
+	Amount      money.Amount
+	Type        Type
+	Category    string
+	Description string
+	OccurredAt  time.Time
+}
+
+// UpdateParams carries a partial update scoped to a single user's transaction.
+type UpdateParams struct {
+	ID          string
+	UserID      string
+	Amount      *money.Amount
+	Type        *Type
+	Category    *string
+	Description *string
+	OccurredAt  *time.Time
+}
+
+// ── Contracts ─────────────────────────────────────────────────────────────────
+
+// Repository defines the data-access contract for the transactions domain.
+// Every method is scoped to a user ID so one user can never read or modify
+// another user's records.
+type Repository interface {
+	Create(ctx context.Context, params CreateParams) (Transaction, error)
+	Get(ctx context.Context, userID, id string) (Transaction, error)
+	List(ctx context.Context, userID string, limit, offset int) ([]Transaction, error)
+	Update(ctx context.Context, params UpdateParams) (Transaction, error)
+	Delete(ctx context.Context, userID, id string) error
+}
+
+// Service defines the business-logic contract for the transactions domain.
+type Service interface {
+	Create(ctx context.Context, userID string, input CreateInput) (TransactionResponse, error)
+	Get(ctx context.Context, userID, id string) (TransactionResponse, error)
+	List(ctx context.Context, userID string, input ListInput) (ListResponse, error)
+	Update(ctx context.Context, userID, id string, input UpdateInput) (TransactionResponse, error)
+	Delete(ctx context.Context, userID, id string) error
+}
@@ -0,0 +1,225 @@
+package users
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png" // registers the PNG decoder
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registers the WebP decoder
+)
+
+// avatarQuality is the JPEG quality thumbnails are encoded with.
+const avatarQuality = 85
+
+// minAvatarDimension is the smallest accepted width or height, in pixels.
+const minAvatarDimension = 32
+
+// avatarSizes are the edges, in pixels, of the square thumbnails generated
+// from each upload: AvatarURLs' small, medium and large.
+var avatarSizes = [3]int{64, 128, 256}
+
+// avatarFormats maps sniffed content types onto the image package's format names.
+var avatarFormats = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+	"image/webp": "webp",
+}
+
+// avatarThumbnail is one encoded thumbnail, ready to store.
+type avatarThumbnail struct {
+	size int
+	data []byte
+}
+
+// avatarThumbnailKey is where the thumbnail of size pixels lives under an avatar key.
+func avatarThumbnailKey(avatarKey string, size int) string {
+	return avatarKey + "/" + strconv.Itoa(size) + ".jpg"
+}
+
+// processAvatar checks an uploaded image and renders it into every avatarSize.
+// The type is sniffed from the bytes, never taken from the client, and the
+// dimensions are read from the header before any pixels are decoded. Output is
+// always re-encoded JPEG, which drops EXIF and any other embedded metadata;
+// the EXIF orientation of JPEG uploads is applied first so photos stay upright.
+func processAvatar(data []byte, maxDimension int) ([]avatarThumbnail, error) {
+	format, ok := avatarFormats[http.DetectContentType(data)]
+	if !ok {
+		return nil, ErrUnsupportedImage
+	}
+
+	cfg, decoded, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || decoded != format {
+		return nil, ErrUnsupportedImage
+	}
+	if cfg.Width < minAvatarDimension || cfg.Height < minAvatarDimension ||
+		cfg.Width > maxDimension || cfg.Height > maxDimension {
+		return nil, ErrInvalidImageDimensions
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	orientation := 1
+	if format == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+
+	// centre square crop; orientation commutes with it, so it is applied to
+	// the small thumbnails rather than the full image
+	b := img.Bounds()
+	side := min(b.Dx(), b.Dy())
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(b.Min.X+(b.Dx()-side)/2, b.Min.Y+(b.Dy()-side)/2))
+
+	thumbnails := make([]avatarThumbnail, 0, len(avatarSizes))
+	for _, size := range avatarSizes {
+		dst := image.NewRGBA(image.Rect(0, 0, size, size))
+		// transparent areas are flattened onto white, since JPEG has no alpha
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.CatmullRom.Scale(dst, dst.Bounds(), img, crop, draw.Over, nil)
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, orient(dst, orientation), &jpeg.Options{Quality: avatarQuality}); err != nil {
+			return nil, err
+		}
+		thumbnails = append(thumbnails, avatarThumbnail{size: size, data: buf.Bytes()})
+	}
+	return thumbnails, nil
+}
+
+// jpegOrientation returns the EXIF orientation (1-8) of a JPEG, or 1 when it
+// has none or the EXIF block is malformed.
+func jpegOrientation(data []byte) int {
+	// walk the marker segments up to the start of scan
+	for i := 2; i+4 <= len(data) && data[i] == 0xFF; {
+		marker := data[i+1]
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if marker == 0xDA || length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// exifOrientation reads tag 0x0112 from IFD0 of a TIFF-structured EXIF block.
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for n := range entries {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if v := int(order.Uint16(tiff[entry+8:])); v >= 1 && v <= 8 {
+				return v
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// orient returns the square image src turned upright for an EXIF orientation.
+func orient(src *image.RGBA, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+
+	n := src.Bounds().Dx() - 1
+	dst := image.NewRGBA(src.Bounds())
+	for y := 0; y <= n; y++ {
+		for x := 0; x <= n; x++ {
+			// (sx, sy) is the stored pixel shown at (x, y)
+			var sx, sy int
+			switch orientation {
+			case 2: // mirrored horizontally
+				sx, sy = n-x, y
+			case 3: // rotated 180°
+				sx, sy = n-x, n-y
+			case 4: // mirrored vertically
+				sx, sy = x, n-y
+			case 5: // mirrored along the main diagonal
+				sx, sy = y, x
+			case 6: // stored rotated 90° counter-clockwise
+				sx, sy = y, n-x
+			case 7: // mirrored along the anti-diagonal
+				sx, sy = n-y, n-x
+			case 8: // stored rotated 90° clockwise
+				sx, sy = n-y, x
+			}
+			dst.SetRGBA(x, y, src.RGBAAt(sx, sy))
+		}
+	}
+	return dst
+}
+
+// avatarSigner issues and checks the signed URLs avatars are served from.
+// Expiry times are rounded up to whole multiples of ttl, so a user's URLs stay
+// identical, and cacheable, for at least ttl.
+type avatarSigner struct {
+	secret  []byte
+	baseURL string
+	ttl     time.Duration
+}
+
+// url returns a signed URL for the blob under key, valid for ttl to 2×ttl.
+func (s avatarSigner) url(key string, now time.Time) string {
+	expires := now.Truncate(s.ttl).Add(2 * s.ttl).Unix()
+	q := url.Values{}
+	q.Set("expires", strconv.FormatInt(expires, 10))
+	q.Set("sig", s.signature(key, expires))
+	return s.baseURL + "/" + key + "?" + q.Encode()
+}
+
+// verify checks a URL's signature and returns its expiry time.
+func (s avatarSigner) verify(key, expires, sig string, now time.Time) (time.Time, bool) {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if !hmac.Equal([]byte(sig), []byte(s.signature(key, unix))) {
+		return time.Time{}, false
+	}
+	expiresAt := time.Unix(unix, 0)
+	return expiresAt, now.Before(expiresAt)
+}
+
+func (s avatarSigner) signature(key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,33 @@
+package users
+
+import "errors"
+
+// Sentinel errors for the users domain.
+var (
+	// ErrNotFound is returned when the user no longer exists.
+	ErrNotFound = errors.New("user not found")
+
+	// ErrInvalidName is returned when a name is blank or longer than maxNameLength.
+	ErrInvalidName = errors.New("name must be 1 to 100 characters")
+
+	// ErrInvalidProfilePicture is returned when a profile picture is neither empty
+	// nor an absolute http(s) URL.
+	ErrInvalidProfilePicture = errors.New("profile_picture must be an http or https URL")
+
+	// ErrAvatarTooLarge is returned when an avatar upload exceeds the size limit.
+	ErrAvatarTooLarge = errors.New("avatar file is too large")
+
+	// ErrUnsupportedImage is returned when an avatar upload is not a decodable
+	// JPEG, PNG or WebP image.
+	ErrUnsupportedImage = errors.New("avatar must be a JPEG, PNG or WebP image")
+
+	// ErrInvalidImageDimensions is returned when an avatar is smaller than
+	// minAvatarDimension or larger than the configured maximum on either side.
+	ErrInvalidImageDimensions = errors.New("avatar dimensions are out of range")
+
+	// ErrInvalidAvatarURL is returned when an avatar URL's signature is wrong or expired.
+	ErrInvalidAvatarURL = errors.New("invalid or expired avatar url")
+
+	// ErrAvatarNotFound is returned when a signed avatar URL points at a deleted image.
+	ErrAvatarNotFound = errors.New("avatar not found")
+)
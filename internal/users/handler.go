@@ -1,20 +1,35 @@
 package users
 
 import (
+	"errors"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/Ajay01103/goTransactonsAPI/internal/auth"
 	jsonutil "github.com/Ajay01103/goTransactonsAPI/internal/json"
+	"github.com/go-chi/chi/v5"
 )
 
+// avatarFormField is the multipart field PUT /users/me/avatar reads the image from.
+const avatarFormField = "avatar"
+
+// multipartOverhead is allowed on top of the avatar size limit for the
+// multipart framing and any other small fields.
+const multipartOverhead = 64 << 10
+
 // Handler holds all HTTP handlers for the users domain.
 type Handler struct {
-	service Service
+	service        Service
+	maxAvatarBytes int64
 }
 
-// NewHandler constructs a Handler with the given users Service.
-func NewHandler(service Service) *Handler {
-	return &Handler{service: service}
+// NewHandler constructs a Handler with the given users Service. Avatar uploads
+// larger than maxAvatarBytes are cut off while reading.
+func NewHandler(service Service, maxAvatarBytes int64) *Handler {
+	return &Handler{service: service, maxAvatarBytes: maxAvatarBytes}
 }
 
 // GetCurrentUser handles GET /users/current-user.
@@ -34,3 +49,152 @@ func (h *Handler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 
 	jsonutil.Write(w, http.StatusOK, user)
 }
+
+type updateProfileRequest struct {
+	Name           *string `json:"name"`
+	ProfilePicture *string `json:"profile_picture"`
+}
+
+// UpdateProfile handles PATCH /users/me. Omitted fields are left untouched.
+func (h *Handler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextKeyUserID).(string)
+	if !ok || userID == "" {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	var req updateProfileRequest
+	if err := jsonutil.Read(r, &req); err != nil {
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	user, err := h.service.UpdateProfile(r.Context(), userID, UpdateProfileInput{
+		Name:           req.Name,
+		ProfilePicture: req.ProfilePicture,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidName), errors.Is(err, ErrInvalidProfilePicture):
+			jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrNotFound):
+			jsonutil.Write(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		default:
+			jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to update profile"})
+		}
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, user)
+}
+
+// UploadAvatar handles PUT /users/me/avatar with a multipart/form-data body
+// whose "avatar" field holds a JPEG, PNG or WebP image.
+func (h *Handler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextKeyUserID).(string)
+	if !ok || userID == "" {
+		jsonutil.Write(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	data, err := h.readAvatar(w, r)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) || errors.Is(err, ErrAvatarTooLarge) {
+			jsonutil.Write(w, http.StatusRequestEntityTooLarge, map[string]string{"error": ErrAvatarTooLarge.Error()})
+			return
+		}
+		jsonutil.Write(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	user, err := h.service.UploadAvatar(r.Context(), userID, data)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrAvatarTooLarge):
+			jsonutil.Write(w, http.StatusRequestEntityTooLarge, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrUnsupportedImage):
+			jsonutil.Write(w, http.StatusUnsupportedMediaType, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrInvalidImageDimensions):
+			jsonutil.Write(w, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrNotFound):
+			jsonutil.Write(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		default:
+			jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to upload avatar"})
+		}
+		return
+	}
+
+	jsonutil.Write(w, http.StatusOK, user)
+}
+
+// readAvatar streams the multipart body to the avatar field and reads it,
+// stopping one byte past the size limit.
+func (h *Handler) readAvatar(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxAvatarBytes+multipartOverhead)
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, errors.New("body must be multipart/form-data")
+	}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil, errors.New(`multipart field "avatar" is required`)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() != avatarFormField {
+			continue
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, h.maxAvatarBytes+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) > h.maxAvatarBytes {
+			return nil, ErrAvatarTooLarge
+		}
+		return data, nil
+	}
+}
+
+// ServeAvatar handles GET /avatars/*, the signed URLs listed in a user's
+// avatar field. Thumbnails never change under a key, so responses may be
+// cached until the URL expires.
+func (h *Handler) ServeAvatar(w http.ResponseWriter, r *http.Request) {
+	key := "avatars/" + chi.URLParam(r, "*")
+	q := r.URL.Query()
+
+	file, err := h.service.OpenAvatar(r.Context(), key, q.Get("expires"), q.Get("sig"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidAvatarURL):
+			jsonutil.Write(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrAvatarNotFound):
+			jsonutil.Write(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		default:
+			jsonutil.Write(w, http.StatusInternalServerError, map[string]string{"error": "failed to load avatar"})
+		}
+		return
+	}
+	defer file.Body.Close()
+
+	maxAge := int(math.Max(0, time.Until(file.ExpiresAt).Seconds()))
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(maxAge)+", immutable")
+	w.Header().Set("ETag", file.ETag)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if r.Header.Get("If-None-Match") == file.ETag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(file.Size, 10))
+	if !file.ModTime.IsZero() {
+		w.Header().Set("Last-Modified", file.ModTime.UTC().Format(http.TimeFormat))
+	}
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, file.Body)
+}
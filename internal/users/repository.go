@@ -2,8 +2,12 @@ package users
 
 import (
 	"context"
+	"errors"
 
+	"github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql"
 	repo "github.com/Ajay01103/goTransactonsAPI/internal/adapters/postgresql/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type postgresRepository struct {
@@ -17,16 +21,59 @@ func NewPostgresRepository(queries *repo.Queries) Repository {
 }
 
 func (r *postgresRepository) GetUserByID(ctx context.Context, id string) (UserRecord, error) {
-	row, err := r.queries.GetUserByID(ctx, id)
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).GetUserByID(ctx, id)
 	if err != nil {
 		return UserRecord{}, err
 	}
 
+	return toRecord(row), nil
+}
+
+func (r *postgresRepository) UpdateProfile(ctx context.Context, params UpdateProfileParams) (UserRecord, error) {
+	args := repo.UpdateUserProfileParams{ID: params.ID}
+	if params.Name != nil {
+		args.Name = pgtype.Text{String: *params.Name, Valid: true}
+	}
+	if params.ProfilePicture != nil {
+		args.ProfilePicture = pgtype.Text{String: *params.ProfilePicture, Valid: true}
+	}
+
+	row, err := postgresql.QueriesFromContext(ctx, r.queries).UpdateUserProfile(ctx, args)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return UserRecord{}, ErrNotFound
+		}
+		return UserRecord{}, err
+	}
+
+	return toRecord(row), nil
+}
+
+func (r *postgresRepository) SetAvatar(ctx context.Context, userID, avatarKey string) (string, error) {
+	previous, err := postgresql.QueriesFromContext(ctx, r.queries).SetUserAvatar(ctx, repo.SetUserAvatarParams{
+		ID:        userID,
+		AvatarKey: pgtype.Text{String: avatarKey, Valid: avatarKey != ""},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+
+	return previous.String, nil
+}
+
+func toRecord(row repo.User) UserRecord {
 	return UserRecord{
 		ID:             row.ID,
 		Name:           row.Name,
 		Email:          row.Email,
 		ProfilePicture: row.ProfilePicture.String,
+		EmailVerified:  row.EmailVerifiedAt.Valid,
+		Role:           row.Role,
 		CreatedAt:      row.CreatedAt.Time.String(),
-	}, nil
+		UpdatedAt:      row.UpdatedAt.Time.String(),
+		AvatarKey:      row.AvatarKey.String,
+	}
 }
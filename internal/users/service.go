@@ -1,17 +1,39 @@
 package users
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/blobstore"
+	"github.com/lucsky/cuid"
 )
 
+// maxNameLength bounds display names, in characters.
+const maxNameLength = 100
+
+// maxProfilePictureLength bounds profile picture URLs, in bytes.
+const maxProfilePictureLength = 2048
+
 type svc struct {
-	repo Repository
+	repo    Repository
+	avatars AvatarConfig
+	signer  avatarSigner
 }
 
-// NewService wires a users Repository into a Service.
-func NewService(repo Repository) Service {
-	return &svc{repo: repo}
+// NewService wires a users Repository and the avatar settings into a Service.
+func NewService(repo Repository, avatars AvatarConfig) Service {
+	return &svc{
+		repo:    repo,
+		avatars: avatars,
+		signer:  avatarSigner{secret: avatars.URLSecret, baseURL: avatars.BaseURL, ttl: avatars.URLTTL},
+	}
 }
 
 // GetCurrentUser fetches the user by ID from the repository.
@@ -21,11 +43,143 @@ func (s *svc) GetCurrentUser(ctx context.Context, userID string) (UserResponse,
 		return UserResponse{}, fmt.Errorf("user not found")
 	}
 
-	return UserResponse{
+	return s.toResponse(user), nil
+}
+
+// UpdateProfile changes the user's name and/or profile picture. Email and
+// password changes go through the auth service, which confirms them first.
+func (s *svc) UpdateProfile(ctx context.Context, userID string, input UpdateProfileInput) (UserResponse, error) {
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+			return UserResponse{}, ErrInvalidName
+		}
+		input.Name = &name
+	}
+	if input.ProfilePicture != nil {
+		picture := strings.TrimSpace(*input.ProfilePicture)
+		if !validProfilePicture(picture) {
+			return UserResponse{}, ErrInvalidProfilePicture
+		}
+		input.ProfilePicture = &picture
+	}
+
+	user, err := s.repo.UpdateProfile(ctx, UpdateProfileParams{
+		ID:             userID,
+		Name:           input.Name,
+		ProfilePicture: input.ProfilePicture,
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return UserResponse{}, err
+		}
+		return UserResponse{}, fmt.Errorf("updating profile: %w", err)
+	}
+
+	return s.toResponse(user), nil
+}
+
+// UploadAvatar replaces the user's avatar with thumbnails of the uploaded
+// image; see processAvatar for the checks applied. Each upload gets a new key,
+// so served URLs never change content and the old files are deleted.
+func (s *svc) UploadAvatar(ctx context.Context, userID string, data []byte) (UserResponse, error) {
+	if int64(len(data)) > s.avatars.MaxBytes {
+		return UserResponse{}, ErrAvatarTooLarge
+	}
+	thumbnails, err := processAvatar(data, s.avatars.MaxDimension)
+	if err != nil {
+		return UserResponse{}, err
+	}
+
+	avatarKey := "avatars/" + userID + "/" + cuid.New()
+	for _, t := range thumbnails {
+		err := s.avatars.Store.Put(ctx, avatarThumbnailKey(avatarKey, t.size), bytes.NewReader(t.data), int64(len(t.data)), "image/jpeg")
+		if err != nil {
+			s.deleteAvatar(ctx, avatarKey)
+			return UserResponse{}, fmt.Errorf("storing avatar: %w", err)
+		}
+	}
+
+	previous, err := s.repo.SetAvatar(ctx, userID, avatarKey)
+	if err != nil {
+		s.deleteAvatar(ctx, avatarKey)
+		if errors.Is(err, ErrNotFound) {
+			return UserResponse{}, err
+		}
+		return UserResponse{}, fmt.Errorf("saving avatar: %w", err)
+	}
+	if previous != "" {
+		s.deleteAvatar(ctx, previous)
+	}
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return UserResponse{}, fmt.Errorf("loading user: %w", err)
+	}
+	return s.toResponse(user), nil
+}
+
+// OpenAvatar checks a signed avatar URL and opens the thumbnail it names.
+func (s *svc) OpenAvatar(ctx context.Context, key, expires, sig string) (AvatarFile, error) {
+	expiresAt, ok := s.signer.verify(key, expires, sig, time.Now())
+	if !ok {
+		return AvatarFile{}, ErrInvalidAvatarURL
+	}
+
+	obj, err := s.avatars.Store.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) {
+			return AvatarFile{}, ErrAvatarNotFound
+		}
+		return AvatarFile{}, fmt.Errorf("opening avatar: %w", err)
+	}
+
+	return AvatarFile{
+		Object: obj,
+		// keys are never reused, so the key itself identifies the content
+		ETag:      `"` + strings.ReplaceAll(strings.TrimPrefix(key, "avatars/"), "/", "-") + `"`,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// deleteAvatar removes an avatar's thumbnails. Failures only leave orphaned
+// files behind, so they are logged rather than returned.
+func (s *svc) deleteAvatar(ctx context.Context, avatarKey string) {
+	for _, size := range avatarSizes {
+		if err := s.avatars.Store.Delete(ctx, avatarThumbnailKey(avatarKey, size)); err != nil {
+			slog.ErrorContext(ctx, "deleting avatar", "key", avatarKey, "error", err)
+		}
+	}
+}
+
+// validProfilePicture accepts an empty string, which removes the picture, or
+// an absolute http(s) URL.
+func validProfilePicture(picture string) bool {
+	if picture == "" {
+		return true
+	}
+	if len(picture) > maxProfilePictureLength {
+		return false
+	}
+	u, err := url.Parse(picture)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func (s *svc) toResponse(user UserRecord) UserResponse {
+	resp := UserResponse{
 		ID:             user.ID,
 		Name:           user.Name,
 		Email:          user.Email,
 		ProfilePicture: user.ProfilePicture,
+		EmailVerified:  user.EmailVerified,
+		Role:           user.Role,
 		CreatedAt:      user.CreatedAt,
-	}, nil
+		UpdatedAt:      user.UpdatedAt,
+	}
+	if user.AvatarKey != "" {
+		now := time.Now()
+		signed := func(size int) string { return s.signer.url(avatarThumbnailKey(user.AvatarKey, size), now) }
+		resp.Avatar = &AvatarURLs{Small: signed(avatarSizes[0]), Medium: signed(avatarSizes[1]), Large: signed(avatarSizes[2])}
+	}
+	return resp
 }
@@ -1,6 +1,23 @@
 package users
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/Ajay01103/goTransactonsAPI/internal/blobstore"
+)
+
+// ── Configuration ─────────────────────────────────────────────────────────────
+
+// AvatarConfig configures avatar uploads and the signed URLs they are served from.
+type AvatarConfig struct {
+	Store        blobstore.BlobStore // holds the generated thumbnails
+	MaxBytes     int64               // largest accepted upload
+	MaxDimension int                 // largest accepted width or height, in pixels
+	URLSecret    []byte              // signs avatar URLs
+	BaseURL      string              // public origin avatar URLs start with, e.g. http://localhost:8000
+	URLTTL       time.Duration       // avatar URLs stay valid and identical for at least this long
+}
 
 // ── Domain model ──────────────────────────────────────────────────────────────
 
@@ -10,18 +27,57 @@ type UserRecord struct {
 	Name           string
 	Email          string
 	ProfilePicture string
+	EmailVerified  bool
+	Role           string
 	CreatedAt      string
+	UpdatedAt      string
+	AvatarKey      string // blob key prefix of the uploaded avatar; empty if none
 }
 
 // ── Service DTO ───────────────────────────────────────────────────────────────
 
 // UserResponse is the public DTO returned from service → handler.
 type UserResponse struct {
-	ID             string `json:"id"`
-	Name           string `json:"name"`
-	Email          string `json:"email"`
-	ProfilePicture string `json:"profile_picture,omitempty"`
-	CreatedAt      string `json:"created_at"`
+	ID             string      `json:"id"`
+	Name           string      `json:"name"`
+	Email          string      `json:"email"`
+	ProfilePicture string      `json:"profile_picture,omitempty"`
+	EmailVerified  bool        `json:"email_verified"`
+	Role           string      `json:"role"`
+	CreatedAt      string      `json:"created_at"`
+	UpdatedAt      string      `json:"updated_at"`
+	Avatar         *AvatarURLs `json:"avatar,omitempty"` // nil until an avatar is uploaded
+}
+
+// AvatarURLs are signed URLs of the uploaded avatar's square thumbnails.
+type AvatarURLs struct {
+	Small  string `json:"small"`  // 64×64
+	Medium string `json:"medium"` // 128×128
+	Large  string `json:"large"`  // 256×256
+}
+
+// AvatarFile is a stored avatar thumbnail opened for serving.
+type AvatarFile struct {
+	blobstore.Object
+	ETag      string
+	ExpiresAt time.Time // when the URL it was requested with stops working
+}
+
+// UpdateProfileInput is the DTO passed from handler → service for a partial
+// profile update. Nil fields are left untouched; an empty ProfilePicture
+// removes the picture.
+type UpdateProfileInput struct {
+	Name           *string
+	ProfilePicture *string
+}
+
+// ── Repository DTOs ───────────────────────────────────────────────────────────
+
+// UpdateProfileParams carries a partial update of a user's profile.
+type UpdateProfileParams struct {
+	ID             string
+	Name           *string
+	ProfilePicture *string
 }
 
 // ── Contracts ─────────────────────────────────────────────────────────────────
@@ -30,9 +86,19 @@ type UserResponse struct {
 // All method signatures use domain types only — no sqlc or pgtype.
 type Repository interface {
 	GetUserByID(ctx context.Context, id string) (UserRecord, error)
+	// UpdateProfile returns ErrNotFound if no user matches.
+	UpdateProfile(ctx context.Context, params UpdateProfileParams) (UserRecord, error)
+	// SetAvatar stores the new avatar key and returns the one it replaced, or
+	// "" if there was none. Returns ErrNotFound if no user matches.
+	SetAvatar(ctx context.Context, userID, avatarKey string) (string, error)
 }
 
 // Service defines the business-logic contract for the users domain.
 type Service interface {
 	GetCurrentUser(ctx context.Context, userID string) (UserResponse, error)
-}
\ No newline at end of file
+	UpdateProfile(ctx context.Context, userID string, input UpdateProfileInput) (UserResponse, error)
+	UploadAvatar(ctx context.Context, userID string, data []byte) (UserResponse, error)
+	// OpenAvatar checks a signed avatar URL and opens the thumbnail it names.
+	// The caller must close the returned file's Body.
+	OpenAvatar(ctx context.Context, key, expires, sig string) (AvatarFile, error)
+}